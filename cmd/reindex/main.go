@@ -0,0 +1,48 @@
+// Command reindex rebuilds the search index from the contacts in the
+// database. Run it with the same SEARCH_BACKEND and SEARCH_INDEX_PATH as the
+// server while the server is stopped; a running server holds the index open
+// and rebuilds it through POST /api/v1/contacts/reindex instead.
+package main
+
+import (
+	"errors"
+	"log"
+
+	"api-contacts-go/internal/config"
+	"api-contacts-go/internal/database"
+	"api-contacts-go/internal/services"
+
+	"github.com/sirupsen/logrus"
+)
+
+func main() {
+	cfg := config.Load()
+
+	index, err := services.NewSearchIndex(cfg.SearchBackend, cfg.SearchIndexPath)
+	if errors.Is(err, services.ErrSearchIndexLocked) {
+		log.Fatal("The API is running with this search index, rebuild it with POST /api/v1/contacts/reindex instead: ", err)
+	}
+	if err != nil {
+		log.Fatal("Failed to open search index:", err)
+	}
+	if _, ok := index.(services.SQLIndex); ok {
+		logrus.Info("The sql search backend searches the database directly, there is no index to rebuild")
+		return
+	}
+
+	db, err := database.Initialize(cfg.DatabaseURL)
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+
+	indexed, err := services.NewContactService(db, index).Reindex()
+	if err != nil {
+		log.Fatal("Failed to rebuild search index:", err)
+	}
+	if disk, ok := index.(*services.DiskIndex); ok {
+		if err := disk.Close(); err != nil {
+			log.Fatal("Failed to close search index:", err)
+		}
+	}
+	logrus.Infof("Indexed %d contacts into %s", indexed, cfg.SearchIndexPath)
+}
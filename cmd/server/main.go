@@ -3,11 +3,13 @@ package main
 import (
 	"log"
 	"os"
+	"time"
 
 	"api-contacts-go/internal/config"
 	"api-contacts-go/internal/database"
 	"api-contacts-go/internal/handlers"
 	"api-contacts-go/internal/middleware"
+	"api-contacts-go/internal/services"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
@@ -16,6 +18,9 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// searchIndexRetry is how often index updates that failed are retried
+const searchIndexRetry = time.Minute
+
 func main() {
 	// Load configuration
 	cfg := config.Load()
@@ -37,6 +42,43 @@ func main() {
 		logrus.Warn("Failed to run migrations:", err)
 	}
 
+	// Open the search index
+	index, err := services.NewSearchIndex(cfg.SearchBackend, cfg.SearchIndexPath)
+	if err != nil {
+		log.Fatal("Failed to open search index:", err)
+	}
+	contacts := services.NewContactService(db, index)
+
+	// Fill in search keys the migrations cannot compute
+	if updated, err := contacts.BackfillPhoneticKeys(); err != nil {
+		logrus.Warn("Failed to backfill phonetic keys:", err)
+	} else if updated > 0 {
+		logrus.Infof("Backfilled phonetic keys of %d contacts", updated)
+	}
+
+	// Build the search index on first use, or catch it up with the changes
+	// it missed
+	if disk, ok := index.(*services.DiskIndex); ok && disk.Empty() {
+		indexed, err := contacts.Reindex()
+		if err != nil {
+			log.Fatal("Failed to build search index:", err)
+		}
+		logrus.Infof("Built search index of %d contacts", indexed)
+	} else if synced, err := contacts.SyncSearchIndex(); err != nil {
+		log.Fatal("Failed to update search index:", err)
+	} else if synced > 0 {
+		logrus.Infof("Updated the search index of %d contacts", synced)
+	}
+
+	// Retry the index updates that failed while serving
+	go func() {
+		for range time.Tick(searchIndexRetry) {
+			if _, err := contacts.SyncSearchIndex(); err != nil {
+				logrus.Warn("Failed to update search index:", err)
+			}
+		}
+	}()
+
 	// Create Fiber app
 	app := fiber.New(fiber.Config{
 		ErrorHandler: middleware.ErrorHandler,
@@ -65,7 +107,7 @@ func main() {
 
 	// API routes
 	api := app.Group("/api/v1")
-	handlers.SetupRoutes(api, db)
+	handlers.SetupRoutes(api, db, index)
 
 	// Start server
 	port := os.Getenv("PORT")
@@ -6,17 +6,29 @@ import (
 )
 
 type Config struct {
-	DatabaseURL string
-	Port        string
-	Environment string
+	DatabaseURL     string
+	Port            string
+	Environment     string
+	SearchBackend   string
+	SearchIndexPath string
 }
 
+// defaultSearchIndexPath is where the embedded search index lives unless
+// SEARCH_INDEX_PATH says otherwise
+const defaultSearchIndexPath = "data/search.idx"
+
 func Load() *Config {
-	return &Config{
-		DatabaseURL: normalizeDatabaseURL(os.Getenv("DATABASE")),
-		Port:        os.Getenv("PORT"),
-		Environment: os.Getenv("ENVIRONMENT"),
+	cfg := &Config{
+		DatabaseURL:     normalizeDatabaseURL(os.Getenv("DATABASE")),
+		Port:            os.Getenv("PORT"),
+		Environment:     os.Getenv("ENVIRONMENT"),
+		SearchBackend:   os.Getenv("SEARCH_BACKEND"),
+		SearchIndexPath: os.Getenv("SEARCH_INDEX_PATH"),
+	}
+	if cfg.SearchIndexPath == "" {
+		cfg.SearchIndexPath = defaultSearchIndexPath
 	}
+	return cfg
 }
 
 // normalizeDatabaseURL fixes common SSL parameter issues in PostgreSQL connection strings
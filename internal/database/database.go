@@ -37,6 +37,12 @@ func Initialize(databaseURL string) (*gorm.DB, error) {
 }
 
 func RunMigrations(databaseURL string) error {
+	return RunMigrationsFrom(databaseURL, "migrations")
+}
+
+// RunMigrationsFrom applies the migrations found in dir, relative to the
+// working directory
+func RunMigrationsFrom(databaseURL, dir string) error {
 	// Open database connection for migrations
 	db, err := sql.Open("postgres", databaseURL)
 	if err != nil {
@@ -51,7 +57,7 @@ func RunMigrations(databaseURL string) error {
 	}
 
 	m, err := migrate.NewWithDatabaseInstance(
-		"file://migrations",
+		"file://"+dir,
 		"postgres", driver)
 	if err != nil {
 		return fmt.Errorf("failed to create migrate instance: %w", err)
@@ -0,0 +1,126 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Operator compares a field with one or more values
+type Operator string
+
+// Operators accepted in comparisons
+const (
+	OpEqual        Operator = "="
+	OpNotEqual     Operator = "!="
+	OpLess         Operator = "<"
+	OpLessEqual    Operator = "<="
+	OpGreater      Operator = ">"
+	OpGreaterEqual Operator = ">="
+	OpContains     Operator = "contains"
+	OpStartsWith   Operator = "starts with"
+	OpEndsWith     Operator = "ends with"
+	OpIn           Operator = "in"
+)
+
+// FieldType decides which operators and values a field accepts
+type FieldType int
+
+const (
+	FieldText FieldType = iota
+	FieldNumber
+	FieldTime
+)
+
+// fieldOperators lists the operators each field type supports
+var fieldOperators = map[FieldType][]Operator{
+	FieldText:   {OpEqual, OpNotEqual, OpContains, OpStartsWith, OpEndsWith, OpIn},
+	FieldNumber: {OpEqual, OpNotEqual, OpLess, OpLessEqual, OpGreater, OpGreaterEqual, OpIn},
+	FieldTime:   {OpEqual, OpNotEqual, OpLess, OpLessEqual, OpGreater, OpGreaterEqual},
+}
+
+// Expr is a node of a parsed filter expression
+type Expr interface {
+	// Pos is the 1-based character offset of the node in the input
+	Pos() int
+	String() string
+}
+
+// Logical joins two expressions with "and" or "or"
+type Logical struct {
+	Op       string
+	Left     Expr
+	Right    Expr
+	Position int
+}
+
+// Not negates an expression
+type Not struct {
+	Expr     Expr
+	Position int
+}
+
+// Comparison checks a field against its values. In has several values,
+// every other operator exactly one.
+type Comparison struct {
+	Field    string
+	Type     FieldType
+	Operator Operator
+	Values   []Value
+	Position int
+}
+
+// Value is a literal of a comparison, converted for the type of its field
+type Value struct {
+	Text     string
+	Number   int64
+	Time     time.Time
+	DateOnly bool
+	Position int
+}
+
+func (e *Logical) Pos() int    { return e.Position }
+func (e *Not) Pos() int        { return e.Position }
+func (e *Comparison) Pos() int { return e.Position }
+
+// String renders the expression in a form Parse reads back as the same tree,
+// with parentheses only where precedence requires them
+func (e *Logical) String() string {
+	left, right := e.Left.String(), e.Right.String()
+	if l, ok := e.Left.(*Logical); ok && e.Op == "and" && l.Op == "or" {
+		left = "(" + left + ")"
+	}
+	if r, ok := e.Right.(*Logical); ok && (e.Op == "and" || r.Op == "or") {
+		right = "(" + right + ")"
+	}
+	return fmt.Sprintf("%s %s %s", left, e.Op, right)
+}
+
+func (e *Not) String() string {
+	if _, ok := e.Expr.(*Logical); ok {
+		return fmt.Sprintf("not (%s)", e.Expr)
+	}
+	return fmt.Sprintf("not %s", e.Expr)
+}
+
+func (e *Comparison) String() string {
+	values := make([]string, 0, len(e.Values))
+	for _, value := range e.Values {
+		values = append(values, quote(value.Text))
+	}
+	if e.Operator == OpIn {
+		return fmt.Sprintf("%s in (%s)", e.Field, strings.Join(values, ", "))
+	}
+	return fmt.Sprintf("%s %s %s", e.Field, e.Operator, strings.Join(values, ", "))
+}
+
+// Error is a problem in a filter expression at a given 1-based character
+// offset of the input
+type Error struct {
+	Position int
+	Message  string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s at position %d", e.Message, e.Position)
+}
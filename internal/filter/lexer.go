@@ -0,0 +1,126 @@
+package filter
+
+import (
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenOperator
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+// token is a lexical unit of a filter expression. Position is the 1-based
+// character offset of its first character in the input.
+type token struct {
+	kind     tokenKind
+	text     string
+	position int
+}
+
+// describe names a token for error messages
+func (t token) describe() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of filter"
+	case tokenString:
+		return "string " + quote(t.text)
+	}
+	return "'" + t.text + "'"
+}
+
+// isWordRune reports whether r can be part of a bare word: field names,
+// keywords, numbers and unquoted dates or timestamps
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_.-:+@", r)
+}
+
+// lex splits a filter expression into tokens, ending with tokenEOF
+func lex(input string) ([]token, error) {
+	runes := []rune(input)
+	var tokens []token
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		position := i + 1
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{tokenLParen, "(", position})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{tokenRParen, ")", position})
+			i++
+		case r == ',':
+			tokens = append(tokens, token{tokenComma, ",", position})
+			i++
+		case r == '=':
+			tokens = append(tokens, token{tokenOperator, "=", position})
+			i++
+		case r == '!' || r == '<' || r == '>':
+			op := string(r)
+			if i+1 < len(runes) && runes[i+1] == '=' {
+				op += "="
+			} else if r == '!' {
+				return nil, &Error{Position: position, Message: "expected '!='"}
+			}
+			tokens = append(tokens, token{tokenOperator, op, position})
+			i += len(op)
+		case r == '\'' || r == '"':
+			text, next, err := lexString(runes, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{tokenString, text, position})
+			i = next
+		case isWordRune(r):
+			start := i
+			for i < len(runes) && isWordRune(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, token{tokenWord, string(runes[start:i]), position})
+		default:
+			return nil, &Error{Position: position, Message: "unexpected character " + quote(string(r))}
+		}
+	}
+
+	return append(tokens, token{tokenEOF, "", len(runes) + 1}), nil
+}
+
+// lexString reads the quoted string starting at runes[start], where a
+// backslash escapes the next character, and returns the index after it
+func lexString(runes []rune, start int) (string, int, error) {
+	delimiter := runes[start]
+	var b strings.Builder
+
+	for i := start + 1; i < len(runes); i++ {
+		switch runes[i] {
+		case '\\':
+			if i+1 == len(runes) {
+				return "", 0, &Error{Position: i + 1, Message: "unfinished escape sequence"}
+			}
+			i++
+			b.WriteRune(runes[i])
+		case delimiter:
+			return b.String(), i + 1, nil
+		default:
+			b.WriteRune(runes[i])
+		}
+	}
+
+	return "", 0, &Error{Position: start + 1, Message: "unterminated string"}
+}
+
+// quote renders a string literal that lex reads back as the same text
+func quote(text string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(text) + "'"
+}
@@ -0,0 +1,249 @@
+// Package filter parses the filter expressions accepted by the contacts
+// listing, such as
+//
+//	(company = 'Tech Corp' or email ends with '@acme.com') and created_at > 2025-01-01
+//
+// Comparisons join with "and", "or" and "not" (in increasing precedence) and
+// parentheses. Fields are checked against a whitelist given by the caller,
+// and values are converted for the type of their field, so the resulting
+// tree can be translated into SQL without further checks.
+package filter
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limits on the size of an expression
+const (
+	MaxLength   = 2000
+	MaxDepth    = 32
+	MaxInValues = 100
+)
+
+// DateLayout is the format of dates compared with time fields, which also
+// accept RFC 3339 timestamps
+const DateLayout = "2006-01-02"
+
+type parser struct {
+	tokens []token
+	pos    int
+	depth  int
+	fields map[string]FieldType
+}
+
+// Parse reads a filter expression, accepting only the given fields
+func Parse(input string, fields map[string]FieldType) (Expr, error) {
+	if len([]rune(input)) > MaxLength {
+		return nil, &Error{Position: MaxLength + 1, Message: fmt.Sprintf("filter is longer than %d characters", MaxLength)}
+	}
+
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens, fields: fields}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if next := p.peek(); next.kind != tokenEOF {
+		return nil, p.unexpected(next, "'and', 'or' or end of filter")
+	}
+	return expr, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// keyword reports whether t is the given keyword, ignoring case
+func keyword(t token, word string) bool {
+	return t.kind == tokenWord && strings.EqualFold(t.text, word)
+}
+
+func (p *parser) unexpected(t token, expected string) error {
+	return &Error{Position: t.position, Message: fmt.Sprintf("expected %s, found %s", expected, t.describe())}
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for keyword(p.peek(), "or") {
+		op := p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &Logical{Op: "or", Left: left, Right: right, Position: op.position}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for keyword(p.peek(), "and") {
+		op := p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &Logical{Op: "and", Left: left, Right: right, Position: op.position}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	// Nesting is bounded so hostile input cannot exhaust the stack
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.depth > MaxDepth {
+		return nil, &Error{Position: p.peek().position, Message: fmt.Sprintf("filter is nested deeper than %d levels", MaxDepth)}
+	}
+
+	t := p.peek()
+	if keyword(t, "not") {
+		p.next()
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &Not{Expr: expr, Position: t.position}, nil
+	}
+
+	if t.kind == tokenLParen {
+		p.next()
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, p.unexpected(closing, "')'")
+		}
+		return expr, nil
+	}
+
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (Expr, error) {
+	field := p.next()
+	if field.kind != tokenWord {
+		return nil, p.unexpected(field, "a field name")
+	}
+	name := strings.ToLower(field.text)
+	fieldType, ok := p.fields[name]
+	if !ok {
+		return nil, &Error{Position: field.position, Message: fmt.Sprintf("unknown field '%s'", field.text)}
+	}
+
+	opToken := p.peek()
+	op, err := p.parseOperator()
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(fieldOperators[fieldType], op) {
+		return nil, &Error{Position: opToken.position, Message: fmt.Sprintf("operator '%s' does not apply to field '%s'", op, name)}
+	}
+
+	comparison := &Comparison{Field: name, Type: fieldType, Operator: op, Position: field.position}
+	if op != OpIn {
+		value, err := p.parseValue(fieldType)
+		if err != nil {
+			return nil, err
+		}
+		comparison.Values = []Value{value}
+		return comparison, nil
+	}
+
+	if open := p.next(); open.kind != tokenLParen {
+		return nil, p.unexpected(open, "'(' after 'in'")
+	}
+	for {
+		value, err := p.parseValue(fieldType)
+		if err != nil {
+			return nil, err
+		}
+		if len(comparison.Values) == MaxInValues {
+			return nil, &Error{Position: value.Position, Message: fmt.Sprintf("'in' accepts at most %d values", MaxInValues)}
+		}
+		comparison.Values = append(comparison.Values, value)
+
+		separator := p.next()
+		if separator.kind == tokenRParen {
+			return comparison, nil
+		}
+		if separator.kind != tokenComma {
+			return nil, p.unexpected(separator, "',' or ')'")
+		}
+	}
+}
+
+func (p *parser) parseOperator() (Operator, error) {
+	t := p.next()
+	if t.kind == tokenOperator {
+		return Operator(t.text), nil
+	}
+
+	switch {
+	case keyword(t, "contains"):
+		return OpContains, nil
+	case keyword(t, "in"):
+		return OpIn, nil
+	case keyword(t, "starts"), keyword(t, "ends"):
+		if with := p.next(); !keyword(with, "with") {
+			return "", p.unexpected(with, "'with'")
+		}
+		if keyword(t, "starts") {
+			return OpStartsWith, nil
+		}
+		return OpEndsWith, nil
+	}
+	return "", p.unexpected(t, "an operator")
+}
+
+// parseValue reads a quoted string or bare word and converts it for the field
+func (p *parser) parseValue(fieldType FieldType) (Value, error) {
+	t := p.next()
+	if t.kind != tokenString && t.kind != tokenWord {
+		return Value{}, p.unexpected(t, "a value")
+	}
+	value := Value{Text: t.text, Position: t.position}
+
+	switch fieldType {
+	case FieldNumber:
+		number, err := strconv.ParseInt(t.text, 10, 64)
+		if err != nil {
+			return Value{}, &Error{Position: t.position, Message: fmt.Sprintf("%s is not a whole number", t.describe())}
+		}
+		value.Number = number
+	case FieldTime:
+		if date, err := time.Parse(DateLayout, t.text); err == nil {
+			value.Time = date
+			value.DateOnly = true
+		} else if timestamp, err := time.Parse(time.RFC3339, t.text); err == nil {
+			value.Time = timestamp
+		} else {
+			return Value{}, &Error{Position: t.position, Message: fmt.Sprintf("%s is not a date (YYYY-MM-DD) or RFC 3339 timestamp", t.describe())}
+		}
+	}
+
+	return value, nil
+}
@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"strconv"
+
+	"api-contacts-go/internal/models"
+	"api-contacts-go/internal/services"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type AddressHandler struct {
+	service   *services.AddressService
+	validator *validator.Validate
+}
+
+func NewAddressHandler(db *gorm.DB) *AddressHandler {
+	return &AddressHandler{
+		service:   services.NewAddressService(db),
+		validator: newValidator(),
+	}
+}
+
+// GetAddresses godoc
+// @Summary Get contact addresses
+// @Description Get the postal addresses of a contact
+// @Tags addresses
+// @Accept json
+// @Produce json
+// @Param id path int true "Contact ID"
+// @Success 200 {array} models.Address
+// @Failure 404 {object} map[string]string
+// @Router /contacts/{id}/addresses [get]
+func (h *AddressHandler) GetAddresses(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid contact ID",
+		})
+	}
+
+	addresses, err := h.service.GetAddresses(uint(id))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Contact not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch addresses",
+		})
+	}
+
+	return c.JSON(addresses)
+}
+
+// CreateAddress godoc
+// @Summary Add an address
+// @Description Add a postal address to a contact
+// @Tags addresses
+// @Accept json
+// @Produce json
+// @Param id path int true "Contact ID"
+// @Param address body models.AddressRequest true "Address data"
+// @Success 201 {object} models.Address
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /contacts/{id}/addresses [post]
+func (h *AddressHandler) CreateAddress(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid contact ID",
+		})
+	}
+
+	var req models.AddressRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	req.Normalize()
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Validation failed",
+			"details": err.Error(),
+		})
+	}
+
+	address, err := h.service.CreateAddress(uint(id), &req)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Contact not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create address",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(address)
+}
+
+// UpdateAddress godoc
+// @Summary Update an address
+// @Description Replace a postal address of a contact
+// @Tags addresses
+// @Accept json
+// @Produce json
+// @Param id path int true "Contact ID"
+// @Param addressId path int true "Address ID"
+// @Param address body models.AddressRequest true "Address data"
+// @Success 200 {object} models.Address
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /contacts/{id}/addresses/{addressId} [put]
+func (h *AddressHandler) UpdateAddress(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid contact ID",
+		})
+	}
+
+	addressID, err := strconv.ParseUint(c.Params("addressId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid address ID",
+		})
+	}
+
+	var req models.AddressRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	req.Normalize()
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Validation failed",
+			"details": err.Error(),
+		})
+	}
+
+	address, err := h.service.UpdateAddress(uint(id), uint(addressID), &req)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Contact not found",
+			})
+		}
+		if err == services.ErrAddressNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Address not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update address",
+		})
+	}
+
+	return c.JSON(address)
+}
+
+// DeleteAddress godoc
+// @Summary Delete an address
+// @Description Remove a postal address from a contact
+// @Tags addresses
+// @Accept json
+// @Produce json
+// @Param id path int true "Contact ID"
+// @Param addressId path int true "Address ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Router /contacts/{id}/addresses/{addressId} [delete]
+func (h *AddressHandler) DeleteAddress(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid contact ID",
+		})
+	}
+
+	addressID, err := strconv.ParseUint(c.Params("addressId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid address ID",
+		})
+	}
+
+	if err := h.service.DeleteAddress(uint(id), uint(addressID)); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Contact not found",
+			})
+		}
+		if err == services.ErrAddressNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Address not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to delete address",
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"strconv"
+
+	"api-contacts-go/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// Autocomplete godoc
+// @Summary Suggest contacts
+// @Description Suggest contacts whose name, a word of the name, or email starts with the prefix, favoring the often selected and recently updated ones
+// @Tags contacts
+// @Accept json
+// @Produce json
+// @Param prefix query string true "Typed prefix"
+// @Param limit query int false "Number of suggestions" default(8)
+// @Success 200 {object} models.AutocompleteResponse
+// @Failure 400 {object} map[string]string
+// @Router /contacts/autocomplete [get]
+func (h *ContactHandler) Autocomplete(c *fiber.Ctx) error {
+	prefix := c.Query("prefix")
+	if prefix == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "prefix is required",
+		})
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit", "8"))
+	if limit < 1 || limit > 20 {
+		limit = 8
+	}
+
+	contacts, err := h.service.Autocomplete(prefix, limit)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to suggest contacts",
+		})
+	}
+
+	suggestions := make([]models.ContactSuggestion, 0, len(contacts))
+	for _, contact := range contacts {
+		suggestions = append(suggestions, models.ContactSuggestion{ID: contact.ID, Name: contact.Name, Email: contact.Email})
+	}
+
+	return c.JSON(models.AutocompleteResponse{Data: suggestions})
+}
+
+// SelectContact godoc
+// @Summary Record a suggestion pick
+// @Description Record that a contact was picked from the suggestions, boosting it in later ones
+// @Tags contacts
+// @Accept json
+// @Produce json
+// @Param id path int true "Contact ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Router /contacts/{id}/selections [post]
+func (h *ContactHandler) SelectContact(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid contact ID",
+		})
+	}
+
+	if err := h.service.RecordSelection(uint(id)); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Contact not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to record selection",
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"api-contacts-go/internal/models"
+)
+
+// calendarFallbackYear starts the events of dates without a year. It is a
+// leap year so that February 29 is a valid start date.
+const calendarFallbackYear = 2000
+
+// renderCalendar writes the dates as an RFC 5545 calendar of yearly all-day events
+func renderCalendar(dates []models.ContactDate, now time.Time) string {
+	var b strings.Builder
+	stamp := now.UTC().Format("20060102T150405Z")
+
+	writeLine := func(line string) {
+		b.WriteString(foldLine(line))
+		b.WriteString("\r\n")
+	}
+
+	writeLine("BEGIN:VCALENDAR")
+	writeLine("VERSION:2.0")
+	writeLine("PRODID:-//api-contacts-go//Contact dates//EN")
+	writeLine("CALSCALE:GREGORIAN")
+	writeLine("X-WR-CALNAME:Contacts")
+
+	for _, date := range dates {
+		year := calendarFallbackYear
+		if date.Date.Year != nil {
+			year = *date.Date.Year
+		}
+		start := date.Date.In(year, time.UTC)
+
+		summary := date.ContactName + " - " + date.Label
+		uid := fmt.Sprintf("date-%d@api-contacts-go", date.ID)
+		if date.Kind == models.DateKindBirthday {
+			summary = date.ContactName + "'s birthday"
+			uid = fmt.Sprintf("birthday-%d@api-contacts-go", date.ContactID)
+		}
+
+		// Yearly rules skip February 29 in common years, so those dates
+		// recur on the last day of February instead
+		rule := "RRULE:FREQ=YEARLY"
+		if date.Date.Month == 2 && date.Date.Day == 29 {
+			rule = "RRULE:FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=-1"
+		}
+
+		writeLine("BEGIN:VEVENT")
+		writeLine("UID:" + uid)
+		writeLine("DTSTAMP:" + stamp)
+		writeLine("DTSTART;VALUE=DATE:" + start.Format("20060102"))
+		writeLine("DTEND;VALUE=DATE:" + start.AddDate(0, 0, 1).Format("20060102"))
+		writeLine(rule)
+		writeLine("SUMMARY:" + escapeCalendarText(summary))
+		writeLine("TRANSP:TRANSPARENT")
+		writeLine("END:VEVENT")
+	}
+
+	writeLine("END:VCALENDAR")
+	return b.String()
+}
+
+func escapeCalendarText(text string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(text)
+}
+
+// foldLine splits content lines longer than 75 octets without breaking
+// multi-byte characters, continuing them on lines starting with a space
+func foldLine(line string) string {
+	if len(line) <= 75 {
+		return line
+	}
+
+	var b strings.Builder
+	width := 0
+	for _, r := range line {
+		size := len(string(r))
+		if width+size > 75 {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	return b.String()
+}
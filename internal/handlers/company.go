@@ -0,0 +1,294 @@
+package handlers
+
+import (
+	"strconv"
+
+	"api-contacts-go/internal/models"
+	"api-contacts-go/internal/services"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type CompanyHandler struct {
+	service   *services.CompanyService
+	contacts  *services.ContactService
+	validator *validator.Validate
+}
+
+func NewCompanyHandler(db *gorm.DB, index services.SearchIndex) *CompanyHandler {
+	return &CompanyHandler{
+		service:   services.NewCompanyService(db, index),
+		contacts:  services.NewContactService(db, index),
+		validator: newValidator(),
+	}
+}
+
+// GetCompanies godoc
+// @Summary Get all companies
+// @Description Get paginated list of companies with their number of contacts
+// @Tags companies
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} models.PaginatedCompanyResponse
+// @Router /companies [get]
+func (h *CompanyHandler) GetCompanies(c *fiber.Ctx) error {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "10"))
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	companies, total, err := h.service.GetCompanies(page, limit)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch companies",
+		})
+	}
+
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+
+	return c.JSON(models.PaginatedCompanyResponse{
+		Data:       companies,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: totalPages,
+	})
+}
+
+// GetCompany godoc
+// @Summary Get company by ID
+// @Description Get a specific company by ID
+// @Tags companies
+// @Accept json
+// @Produce json
+// @Param id path int true "Company ID"
+// @Success 200 {object} models.Company
+// @Failure 404 {object} map[string]string
+// @Router /companies/{id} [get]
+func (h *CompanyHandler) GetCompany(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid company ID",
+		})
+	}
+
+	company, err := h.service.GetCompany(uint(id))
+	if err != nil {
+		if err == services.ErrCompanyNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Company not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch company",
+		})
+	}
+
+	return c.JSON(company)
+}
+
+// GetCompanyContacts godoc
+// @Summary Get company contacts
+// @Description Get paginated list of the contacts linked to a company
+// @Tags companies
+// @Accept json
+// @Produce json
+// @Param id path int true "Company ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} models.PaginatedResponse
+// @Failure 404 {object} map[string]string
+// @Router /companies/{id}/contacts [get]
+func (h *CompanyHandler) GetCompanyContacts(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid company ID",
+		})
+	}
+
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "10"))
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	company, err := h.service.GetCompany(uint(id))
+	if err != nil {
+		if err == services.ErrCompanyNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Company not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch contacts",
+		})
+	}
+
+	contacts, total, err := h.contacts.GetContacts(page, limit, services.ContactFilter{CompanyID: &company.ID})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch contacts",
+		})
+	}
+
+	// Convert to response format
+	contactResponses := []models.ContactResponse{}
+	for _, contact := range contacts {
+		contactResponses = append(contactResponses, contact.ToResponse())
+	}
+
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+
+	return c.JSON(models.PaginatedResponse{
+		Data:       contactResponses,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: totalPages,
+	})
+}
+
+// CreateCompany godoc
+// @Summary Create a new company
+// @Description Create a new company
+// @Tags companies
+// @Accept json
+// @Produce json
+// @Param company body models.CreateCompanyRequest true "Company data"
+// @Success 201 {object} models.Company
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /companies [post]
+func (h *CompanyHandler) CreateCompany(c *fiber.Ctx) error {
+	var req models.CreateCompanyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Validation failed",
+			"details": err.Error(),
+		})
+	}
+
+	company, err := h.service.CreateCompany(&req)
+	if err != nil {
+		if err == services.ErrCompanyExists {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": "Company already exists",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create company",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(company)
+}
+
+// UpdateCompany godoc
+// @Summary Update a company
+// @Description Update an existing company, renaming it on its linked contacts too
+// @Tags companies
+// @Accept json
+// @Produce json
+// @Param id path int true "Company ID"
+// @Param company body models.UpdateCompanyRequest true "Company data"
+// @Success 200 {object} models.Company
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /companies/{id} [put]
+func (h *CompanyHandler) UpdateCompany(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid company ID",
+		})
+	}
+
+	var req models.UpdateCompanyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Validation failed",
+			"details": err.Error(),
+		})
+	}
+
+	company, err := h.service.UpdateCompany(uint(id), &req)
+	if err != nil {
+		if err == services.ErrCompanyNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Company not found",
+			})
+		}
+		if err == services.ErrCompanyExists {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": "Company already exists",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update company",
+		})
+	}
+
+	return c.JSON(company)
+}
+
+// DeleteCompany godoc
+// @Summary Delete a company
+// @Description Delete a company, unlinking its contacts
+// @Tags companies
+// @Accept json
+// @Produce json
+// @Param id path int true "Company ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Router /companies/{id} [delete]
+func (h *CompanyHandler) DeleteCompany(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid company ID",
+		})
+	}
+
+	if err := h.service.DeleteCompany(uint(id)); err != nil {
+		if err == services.ErrCompanyNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Company not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to delete company",
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -1,8 +1,13 @@
 package handlers
 
 import (
+	"fmt"
+	"regexp"
 	"strconv"
+	"strings"
+	"time"
 
+	"api-contacts-go/internal/filter"
 	"api-contacts-go/internal/models"
 	"api-contacts-go/internal/services"
 
@@ -12,26 +17,44 @@ import (
 )
 
 type ContactHandler struct {
-	service   *services.ContactService
-	validator *validator.Validate
+	service      *services.ContactService
+	customFields *services.CustomFieldService
+	validator    *validator.Validate
 }
 
-func NewContactHandler(db *gorm.DB) *ContactHandler {
+func NewContactHandler(db *gorm.DB, index services.SearchIndex) *ContactHandler {
 	return &ContactHandler{
-		service:   services.NewContactService(db),
-		validator: validator.New(),
+		service:      services.NewContactService(db, index),
+		customFields: services.NewCustomFieldService(db),
+		validator:    newValidator(),
 	}
 }
 
 // GetContacts godoc
 // @Summary Get all contacts
-// @Description Get paginated list of contacts
+// @Description Get paginated list of contacts, by page or, with a cursor, as a models.CursorPaginatedResponse
 // @Tags contacts
 // @Accept json
 // @Produce json
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
+// @Param tag query []string false "Only contacts with these tags"
+// @Param tag_mode query string false "Match any or all of the tags" Enums(any, all) default(any)
+// @Param cf.{name} query string false "Only contacts whose custom field equals the value"
+// @Param sort query string false "Comma-separated fields to sort by, - for descending" default(-created_at)
+// @Param cursor query string false "Page cursor from next_cursor or prev_cursor, empty for the first page; switches to cursor pagination"
+// @Param fields query string false "Comma-separated response fields to return, all by default"
+// @Param include query string false "Related resources to embed" Enums(relationships)
+// @Param ids query string false "Only these contact IDs, comma-separated"
+// @Param company query string false "Only contacts of this company"
+// @Param email_domain query string false "Only contacts with an email on this domain"
+// @Param has_phone query bool false "Only contacts with or without a phone"
+// @Param created_after query string false "Created after this date (YYYY-MM-DD) or timestamp (RFC 3339)"
+// @Param created_before query string false "Created before this date or timestamp"
+// @Param updated_after query string false "Updated after this date or timestamp"
+// @Param updated_before query string false "Updated before this date or timestamp"
 // @Success 200 {object} models.PaginatedResponse
+// @Failure 400 {object} map[string]string
 // @Router /contacts [get]
 func (h *ContactHandler) GetContacts(c *fiber.Ctx) error {
 	page, _ := strconv.Atoi(c.Query("page", "1"))
@@ -44,7 +67,22 @@ func (h *ContactHandler) GetContacts(c *fiber.Ctx) error {
 		limit = 10
 	}
 
-	contacts, total, err := h.service.GetContacts(page, limit)
+	filter, err := h.parseContactFilter(c, false)
+	if err != nil {
+		if filterErr, ok := err.(*filterError); ok {
+			return c.Status(fiber.StatusBadRequest).JSON(filterErr.response())
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch contacts",
+		})
+	}
+
+	// A cursor parameter, even an empty one, switches to keyset pagination
+	if c.Context().QueryArgs().Has("cursor") {
+		return h.getContactsByCursor(c, limit, filter)
+	}
+
+	contacts, total, err := h.service.GetContacts(page, limit, filter)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to fetch contacts",
@@ -54,7 +92,7 @@ func (h *ContactHandler) GetContacts(c *fiber.Ctx) error {
 	// Convert to response format
 	var contactResponses []models.ContactResponse
 	for _, contact := range contacts {
-		contactResponses = append(contactResponses, contact.ToResponse())
+		contactResponses = append(contactResponses, contact.ToResponse().WithFields(filter.Fields.Names()))
 	}
 
 	totalPages := int((total + int64(limit) - 1) / int64(limit))
@@ -65,9 +103,54 @@ func (h *ContactHandler) GetContacts(c *fiber.Ctx) error {
 		Page:       page,
 		Limit:      limit,
 		TotalPages: totalPages,
+		Sort:       services.FormatSort(filter.Sort),
 	})
 }
 
+// getContactsByCursor answers GetContacts for a cursor, starting from the
+// first page when it is empty
+func (h *ContactHandler) getContactsByCursor(c *fiber.Ctx, limit int, filter services.ContactFilter) error {
+	var cursor *services.Cursor
+	if value := c.Query("cursor"); value != "" {
+		decoded, err := services.DecodeCursor(value, filter.Sort)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid cursor",
+			})
+		}
+		cursor = decoded
+	}
+
+	contacts, next, prev, err := h.service.GetContactsByCursor(cursor, limit, filter)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch contacts",
+		})
+	}
+
+	// Convert to response format
+	contactResponses := []models.ContactResponse{}
+	for _, contact := range contacts {
+		contactResponses = append(contactResponses, contact.ToResponse().WithFields(filter.Fields.Names()))
+	}
+
+	response := models.CursorPaginatedResponse{
+		Data:  contactResponses,
+		Limit: limit,
+		Sort:  services.FormatSort(filter.Sort),
+	}
+	if next != nil {
+		encoded := next.Encode()
+		response.NextCursor = &encoded
+	}
+	if prev != nil {
+		encoded := prev.Encode()
+		response.PrevCursor = &encoded
+	}
+
+	return c.JSON(response)
+}
+
 // GetContact godoc
 // @Summary Get contact by ID
 // @Description Get a specific contact by ID
@@ -110,6 +193,7 @@ func (h *ContactHandler) GetContact(c *fiber.Ctx) error {
 // @Param contact body models.CreateContactRequest true "Contact data"
 // @Success 201 {object} models.ContactResponse
 // @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Router /contacts [post]
 func (h *ContactHandler) CreateContact(c *fiber.Ctx) error {
 	var req models.CreateContactRequest
@@ -120,24 +204,14 @@ func (h *ContactHandler) CreateContact(c *fiber.Ctx) error {
 	}
 
 	// Validate request
-	if err := h.validator.Struct(req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Validation failed",
-			"details": err.Error(),
-		})
-	}
-
-	contact := &models.Contact{
-		Name:    req.Name,
-		Email:   req.Email,
-		Phone:   req.Phone,
-		Company: req.Company,
+	if contactErr := h.validateCreate(&req, "Failed to create contact"); contactErr != nil {
+		return c.Status(contactErr.status).JSON(contactErr.response())
 	}
 
+	contact := newContact(&req)
 	if err := h.service.CreateContact(contact); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to create contact",
-		})
+		contactErr := contactWriteError(err, "Failed to create contact")
+		return c.Status(contactErr.status).JSON(contactErr.response())
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(contact.ToResponse())
@@ -154,6 +228,7 @@ func (h *ContactHandler) CreateContact(c *fiber.Ctx) error {
 // @Success 200 {object} models.ContactResponse
 // @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Router /contacts/{id} [put]
 func (h *ContactHandler) UpdateContact(c *fiber.Ctx) error {
 	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
@@ -171,28 +246,90 @@ func (h *ContactHandler) UpdateContact(c *fiber.Ctx) error {
 	}
 
 	// Validate request
-	if err := h.validator.Struct(req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Validation failed",
-			"details": err.Error(),
-		})
+	if contactErr := h.validateUpdate(&req, "Failed to update contact"); contactErr != nil {
+		return c.Status(contactErr.status).JSON(contactErr.response())
 	}
 
 	contact, err := h.service.UpdateContact(uint(id), &req)
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"error": "Contact not found",
-			})
-		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to update contact",
-		})
+		contactErr := contactWriteError(err, "Failed to update contact")
+		return c.Status(contactErr.status).JSON(contactErr.response())
 	}
 
 	return c.JSON(contact.ToResponse())
 }
 
+// contactError is a rejected contact write, answered with status and an
+// error message, plus details for validation failures
+type contactError struct {
+	status  int
+	message string
+	details string
+}
+
+func (e *contactError) response() fiber.Map {
+	response := fiber.Map{"error": e.message}
+	if e.details != "" {
+		response["details"] = e.details
+	}
+	return response
+}
+
+// validateCreate checks a new contact's fields and custom fields, failure
+// being the message of unexpected errors
+func (h *ContactHandler) validateCreate(req *models.CreateContactRequest, failure string) *contactError {
+	if err := h.validator.Struct(req); err != nil {
+		return &contactError{status: fiber.StatusBadRequest, message: "Validation failed", details: err.Error()}
+	}
+	if err := h.customFields.ValidateValues(req.CustomFields, false); err != nil {
+		return contactWriteError(err, failure)
+	}
+	return nil
+}
+
+// validateUpdate checks the fields and custom fields of a partial update
+func (h *ContactHandler) validateUpdate(req *models.UpdateContactRequest, failure string) *contactError {
+	if err := h.validator.Struct(req); err != nil {
+		return &contactError{status: fiber.StatusBadRequest, message: "Validation failed", details: err.Error()}
+	}
+	if err := h.customFields.ValidateValues(req.CustomFields, true); err != nil {
+		return contactWriteError(err, failure)
+	}
+	return nil
+}
+
+// contactWriteError translates an error creating or updating a contact
+func contactWriteError(err error, failure string) *contactError {
+	if _, ok := err.(*services.CustomFieldError); ok {
+		return &contactError{status: fiber.StatusBadRequest, message: "Validation failed", details: err.Error()}
+	}
+	switch err {
+	case gorm.ErrRecordNotFound:
+		return &contactError{status: fiber.StatusNotFound, message: "Contact not found"}
+	case services.ErrEmailTaken:
+		return &contactError{status: fiber.StatusConflict, message: "Email already in use"}
+	case services.ErrMultiplePrimary, services.ErrEmailRequired, services.ErrCompanyNotFound:
+		return &contactError{status: fiber.StatusBadRequest, message: "Validation failed", details: err.Error()}
+	}
+	return &contactError{status: fiber.StatusInternalServerError, message: failure}
+}
+
+// newContact builds the contact a create request describes
+func newContact(req *models.CreateContactRequest) *models.Contact {
+	contact := &models.Contact{
+		Name:         req.Name,
+		Email:        req.Email,
+		Phone:        req.Phone,
+		Company:      req.Company,
+		CompanyID:    req.CompanyID,
+		Emails:       models.EmailsFromInput(req.Emails),
+		Phones:       models.PhonesFromInput(req.Phones),
+		CustomFields: req.CustomFields,
+	}
+	contact.SetBirthday(req.Birthday)
+	return contact
+}
+
 // DeleteContact godoc
 // @Summary Delete a contact
 // @Description Delete a contact by ID
@@ -227,20 +364,40 @@ func (h *ContactHandler) DeleteContact(c *fiber.Ctx) error {
 
 // SearchContacts godoc
 // @Summary Search contacts
-// @Description Search contacts by name or email
+// @Description Search contacts by name, company or any of their emails and phone numbers
 // @Tags contacts
 // @Accept json
 // @Produce json
 // @Param q query string true "Search query"
+// @Param mode query string false "Full-text, typo-tolerant or sound-alike search" Enums(text, fuzzy, phonetic) default(text)
+// @Param threshold query number false "Minimum similarity of fuzzy matches, from 0 to 1" default(0.3)
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
+// @Param tag query []string false "Only contacts with these tags"
+// @Param tag_mode query string false "Match any or all of the tags" Enums(any, all) default(any)
+// @Param cf.{name} query string false "Only contacts whose custom field equals the value"
+// @Param sort query string false "Comma-separated fields to sort by, - for descending" default(-relevance,-created_at)
+// @Param fields query string false "Comma-separated response fields to return, all by default"
+// @Param include query string false "Related resources to embed" Enums(relationships)
+// @Param ids query string false "Only these contact IDs, comma-separated"
+// @Param company query string false "Only contacts of this company"
+// @Param email_domain query string false "Only contacts with an email on this domain"
+// @Param has_phone query bool false "Only contacts with or without a phone"
+// @Param created_after query string false "Created after this date (YYYY-MM-DD) or timestamp (RFC 3339)"
+// @Param created_before query string false "Created before this date or timestamp"
+// @Param updated_after query string false "Updated after this date or timestamp"
+// @Param updated_before query string false "Updated before this date or timestamp"
+// @Param facets query string false "Comma-separated facets to count over every match" Enums(company, email_domain)
+// @Param facet_size query int false "Buckets per facet" default(10)
+// @Param highlight query bool false "Locate the matched parts of name, email and company" default(false)
 // @Success 200 {object} models.PaginatedResponse
+// @Failure 400 {object} map[string]string
 // @Router /contacts/search [get]
 func (h *ContactHandler) SearchContacts(c *fiber.Ctx) error {
-	query := c.Query("q")
-	if query == "" {
+	opts, err := parseSearchOptions(c)
+	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Search query is required",
+			"error": err.Error(),
 		})
 	}
 
@@ -254,17 +411,55 @@ func (h *ContactHandler) SearchContacts(c *fiber.Ctx) error {
 		limit = 10
 	}
 
-	contacts, total, err := h.service.SearchContacts(query, page, limit)
+	filter, err := h.parseContactFilter(c, true)
+	if err != nil {
+		if filterErr, ok := err.(*filterError); ok {
+			return c.Status(fiber.StatusBadRequest).JSON(filterErr.response())
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to search contacts",
+		})
+	}
+
+	facets, err := services.ParseFacets(c.Query("facets"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	facetSize, _ := strconv.Atoi(c.Query("facet_size", strconv.Itoa(services.DefaultFacetSize)))
+	if facetSize < 1 || facetSize > 100 {
+		facetSize = services.DefaultFacetSize
+	}
+
+	contacts, total, err := h.service.SearchContacts(opts, page, limit, filter)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to search contacts",
 		})
 	}
 
+	// Facets count every match, not only the current page
+	var facetCounts map[string][]models.FacetBucket
+	if len(facets) > 0 {
+		facetCounts, err = h.service.SearchFacets(opts, filter, facets, facetSize)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to search contacts",
+			})
+		}
+	}
+
+	// Highlights are rendered along with any sparse fieldset
+	fields := filter.Fields.Names()
+	if fields != nil && opts.Highlight {
+		fields = append(fields, "highlights")
+	}
+
 	// Convert to response format
 	var contactResponses []models.ContactResponse
 	for _, contact := range contacts {
-		contactResponses = append(contactResponses, contact.ToResponse())
+		contactResponses = append(contactResponses, contact.ToResponse().WithFields(fields))
 	}
 
 	totalPages := int((total + int64(limit) - 1) / int64(limit))
@@ -275,5 +470,227 @@ func (h *ContactHandler) SearchContacts(c *fiber.Ctx) error {
 		Page:       page,
 		Limit:      limit,
 		TotalPages: totalPages,
+		Sort:       services.FormatSort(filter.Sort),
+		Facets:     facetCounts,
 	})
 }
+
+// filterError is a problem with the listing query parameters, reported as 400
+type filterError struct {
+	message string
+	// position locates the problem in a filter expression, when known
+	position int
+}
+
+func (e *filterError) Error() string {
+	return e.message
+}
+
+func (e *filterError) response() fiber.Map {
+	response := fiber.Map{"error": e.message}
+	if e.position > 0 {
+		response["position"] = e.position
+	}
+	return response
+}
+
+// parseSearchOptions reads the text query and search mode of SearchContacts
+func parseSearchOptions(c *fiber.Ctx) (services.SearchOptions, error) {
+	opts := services.SearchOptions{
+		Query: c.Query("q"),
+		Mode:  c.Query("mode", services.SearchModeText),
+	}
+	if opts.Query == "" {
+		return opts, &filterError{message: "Search query is required"}
+	}
+	switch opts.Mode {
+	case services.SearchModeText, services.SearchModeFuzzy, services.SearchModePhonetic:
+	default:
+		return opts, &filterError{message: "mode must be 'text', 'fuzzy' or 'phonetic'"}
+	}
+
+	threshold, err := strconv.ParseFloat(c.Query("threshold", strconv.FormatFloat(services.DefaultFuzzyThreshold, 'f', -1, 64)), 64)
+	if err != nil || threshold < 0 || threshold > 1 {
+		return opts, &filterError{message: "threshold must be a number between 0 and 1"}
+	}
+	opts.Threshold = threshold
+
+	highlight, err := strconv.ParseBool(c.Query("highlight", "false"))
+	if err != nil {
+		return opts, &filterError{message: "highlight must be true or false"}
+	}
+	opts.Highlight = highlight
+
+	return opts, nil
+}
+
+// parseContactFilter reads the listing filters, sort and fieldset shared by
+// GetContacts and SearchContacts, where search allows sorting by relevance
+func (h *ContactHandler) parseContactFilter(c *fiber.Ctx, search bool) (services.ContactFilter, error) {
+	var filter services.ContactFilter
+
+	filter.Sort = services.DefaultContactSort
+	if search {
+		filter.Sort = services.DefaultSearchSort
+	}
+	if value := c.Query("sort"); value != "" {
+		sort, err := services.ParseSort(value, search)
+		if err != nil {
+			return filter, &filterError{message: err.Error()}
+		}
+		filter.Sort = sort
+	}
+
+	var tags []string
+	for _, tag := range c.Context().QueryArgs().PeekMulti("tag") {
+		tags = append(tags, string(tag))
+	}
+	filter.Tags = services.NormalizeTags(tags)
+
+	filter.TagMode = c.Query("tag_mode", services.TagMatchAny)
+	if filter.TagMode != services.TagMatchAny && filter.TagMode != services.TagMatchAll {
+		return filter, &filterError{message: "tag_mode must be 'any' or 'all'"}
+	}
+
+	// Custom fields are filtered with cf.<name>=<value>
+	customFields := map[string]string{}
+	c.Context().QueryArgs().VisitAll(func(key, value []byte) {
+		if name, ok := strings.CutPrefix(string(key), "cf."); ok {
+			customFields[name] = string(value)
+		}
+	})
+
+	customFilters, err := h.customFields.BuildFilters(customFields)
+	if err != nil {
+		if _, ok := err.(*services.CustomFieldError); ok {
+			return filter, &filterError{message: err.Error()}
+		}
+		return filter, err
+	}
+	filter.CustomFields = customFilters
+
+	if err := parseFieldFilters(c, &filter); err != nil {
+		return filter, err
+	}
+
+	fields, err := services.ParseFieldSet(c.Query("fields"), c.Query("include"))
+	if err != nil {
+		if _, ok := err.(*services.FieldSetError); ok {
+			return filter, &filterError{message: err.Error()}
+		}
+		return filter, err
+	}
+	filter.Fields = fields
+
+	return filter, nil
+}
+
+// maxFilterIDs caps how many contacts the ids filter can name
+const maxFilterIDs = 100
+
+var emailDomainPattern = regexp.MustCompile(`^[a-z0-9-]+(\.[a-z0-9-]+)+$`)
+
+// parseFieldFilters reads the filters on contact columns: ids, company,
+// email_domain, has_phone and the created/updated date bounds
+func parseFieldFilters(c *fiber.Ctx, filter *services.ContactFilter) error {
+	if value := c.Query("ids"); value != "" {
+		parts := strings.Split(value, ",")
+		if len(parts) > maxFilterIDs {
+			return &filterError{message: fmt.Sprintf("ids accepts at most %d contacts", maxFilterIDs)}
+		}
+		for _, part := range parts {
+			id, err := strconv.ParseUint(strings.TrimSpace(part), 10, 32)
+			if err != nil || id == 0 {
+				return &filterError{message: fmt.Sprintf("ids must be a comma-separated list of contact IDs, got '%s'", part)}
+			}
+			filter.IDs = append(filter.IDs, uint(id))
+		}
+	}
+
+	filter.Company = strings.TrimSpace(c.Query("company"))
+
+	if value := c.Query("email_domain"); value != "" {
+		domain := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(value), "@"))
+		if !emailDomainPattern.MatchString(domain) {
+			return &filterError{message: fmt.Sprintf("email_domain '%s' is not a valid domain", value)}
+		}
+		filter.EmailDomain = domain
+	}
+
+	if value := c.Query("has_phone"); value != "" {
+		hasPhone, err := strconv.ParseBool(value)
+		if err != nil {
+			return &filterError{message: "has_phone must be true or false"}
+		}
+		filter.HasPhone = &hasPhone
+	}
+
+	bounds := []struct {
+		name  string
+		after bool
+		dest  **time.Time
+	}{
+		{"created_after", true, &filter.CreatedAfter},
+		{"created_before", false, &filter.CreatedBefore},
+		{"updated_after", true, &filter.UpdatedAfter},
+		{"updated_before", false, &filter.UpdatedBefore},
+	}
+	for _, bound := range bounds {
+		value := c.Query(bound.name)
+		if value == "" {
+			continue
+		}
+		t, err := parseTimeBound(value, bound.after)
+		if err != nil {
+			return &filterError{message: fmt.Sprintf("%s must be a date (YYYY-MM-DD) or an RFC 3339 timestamp", bound.name)}
+		}
+		*bound.dest = &t
+	}
+
+	if filter.CreatedAfter != nil && filter.CreatedBefore != nil && !filter.CreatedAfter.Before(*filter.CreatedBefore) {
+		return &filterError{message: "created_after must be earlier than created_before"}
+	}
+	if filter.UpdatedAfter != nil && filter.UpdatedBefore != nil && !filter.UpdatedAfter.Before(*filter.UpdatedBefore) {
+		return &filterError{message: "updated_after must be earlier than updated_before"}
+	}
+
+	if value := c.Query("filter"); value != "" {
+		expr, err := parseFilterExpression(value)
+		if err != nil {
+			return err
+		}
+		filter.Expression = expr
+	}
+
+	return nil
+}
+
+// parseFilterExpression reads a filter expression over the contact fields,
+// pointing at the offending character when it is invalid
+func parseFilterExpression(value string) (filter.Expr, error) {
+	expr, err := filter.Parse(value, services.FilterFields)
+	if err != nil {
+		if exprErr, ok := err.(*filter.Error); ok {
+			return nil, &filterError{message: "Invalid filter: " + exprErr.Error(), position: exprErr.Position}
+		}
+		return nil, err
+	}
+	return expr, nil
+}
+
+// parseTimeBound reads a date or timestamp bound. A date excludes the day
+// itself, so after a date starts on the following day.
+func parseTimeBound(value string, after bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	date, err := time.Parse(services.ConditionDateLayout, value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if after {
+		return date.AddDate(0, 0, 1), nil
+	}
+	return date, nil
+}
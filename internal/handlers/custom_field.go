@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"strconv"
+
+	"api-contacts-go/internal/models"
+	"api-contacts-go/internal/services"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type CustomFieldHandler struct {
+	service   *services.CustomFieldService
+	validator *validator.Validate
+}
+
+func NewCustomFieldHandler(db *gorm.DB) *CustomFieldHandler {
+	return &CustomFieldHandler{
+		service:   services.NewCustomFieldService(db),
+		validator: newValidator(),
+	}
+}
+
+// GetCustomFields godoc
+// @Summary List custom fields
+// @Description List the custom field definitions available on contacts
+// @Tags custom-fields
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.CustomFieldDefinition
+// @Router /custom-fields [get]
+func (h *CustomFieldHandler) GetCustomFields(c *fiber.Ctx) error {
+	fields, err := h.service.GetCustomFields()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch custom fields",
+		})
+	}
+
+	return c.JSON(fields)
+}
+
+// GetCustomField godoc
+// @Summary Get custom field by ID
+// @Description Get a specific custom field definition
+// @Tags custom-fields
+// @Accept json
+// @Produce json
+// @Param id path int true "Custom field ID"
+// @Success 200 {object} models.CustomFieldDefinition
+// @Failure 404 {object} map[string]string
+// @Router /custom-fields/{id} [get]
+func (h *CustomFieldHandler) GetCustomField(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid custom field ID",
+		})
+	}
+
+	field, err := h.service.GetCustomField(uint(id))
+	if err != nil {
+		if err == services.ErrCustomFieldNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Custom field not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch custom field",
+		})
+	}
+
+	return c.JSON(field)
+}
+
+// CreateCustomField godoc
+// @Summary Create a custom field
+// @Description Register a new custom field for contacts
+// @Tags custom-fields
+// @Accept json
+// @Produce json
+// @Param field body models.CreateCustomFieldRequest true "Custom field definition"
+// @Success 201 {object} models.CustomFieldDefinition
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /custom-fields [post]
+func (h *CustomFieldHandler) CreateCustomField(c *fiber.Ctx) error {
+	var req models.CreateCustomFieldRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Validation failed",
+			"details": err.Error(),
+		})
+	}
+
+	field, err := h.service.CreateCustomField(&req)
+	if err != nil {
+		if err == services.ErrCustomFieldExists {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": "Custom field already exists",
+			})
+		}
+		if err == services.ErrEnumValuesNotEnum {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "Validation failed",
+				"details": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create custom field",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(field)
+}
+
+// UpdateCustomField godoc
+// @Summary Update a custom field
+// @Description Update the label, required flag or enum values of a custom field
+// @Tags custom-fields
+// @Accept json
+// @Produce json
+// @Param id path int true "Custom field ID"
+// @Param field body models.UpdateCustomFieldRequest true "Custom field changes"
+// @Success 200 {object} models.CustomFieldDefinition
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /custom-fields/{id} [put]
+func (h *CustomFieldHandler) UpdateCustomField(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid custom field ID",
+		})
+	}
+
+	var req models.UpdateCustomFieldRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Validation failed",
+			"details": err.Error(),
+		})
+	}
+
+	field, err := h.service.UpdateCustomField(uint(id), &req)
+	if err != nil {
+		if err == services.ErrCustomFieldNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Custom field not found",
+			})
+		}
+		if err == services.ErrEnumValuesNotEnum {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "Validation failed",
+				"details": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update custom field",
+		})
+	}
+
+	return c.JSON(field)
+}
+
+// DeleteCustomField godoc
+// @Summary Delete a custom field
+// @Description Delete a custom field and the values stored on contacts
+// @Tags custom-fields
+// @Accept json
+// @Produce json
+// @Param id path int true "Custom field ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Router /custom-fields/{id} [delete]
+func (h *CustomFieldHandler) DeleteCustomField(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid custom field ID",
+		})
+	}
+
+	if err := h.service.DeleteCustomField(uint(id)); err != nil {
+		if err == services.ErrCustomFieldNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Custom field not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to delete custom field",
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,269 @@
+package handlers
+
+import (
+	"strconv"
+	"time"
+
+	"api-contacts-go/internal/models"
+	"api-contacts-go/internal/services"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type DateHandler struct {
+	service   *services.DateService
+	validator *validator.Validate
+}
+
+func NewDateHandler(db *gorm.DB) *DateHandler {
+	return &DateHandler{
+		service:   services.NewDateService(db),
+		validator: newValidator(),
+	}
+}
+
+// GetDates godoc
+// @Summary Get contact dates
+// @Description Get the significant dates of a contact, such as anniversaries
+// @Tags dates
+// @Accept json
+// @Produce json
+// @Param id path int true "Contact ID"
+// @Success 200 {array} models.SignificantDateResponse
+// @Failure 404 {object} map[string]string
+// @Router /contacts/{id}/dates [get]
+func (h *DateHandler) GetDates(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid contact ID",
+		})
+	}
+
+	dates, err := h.service.GetDates(uint(id))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Contact not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch dates",
+		})
+	}
+
+	responses := make([]models.SignificantDateResponse, len(dates))
+	for i, date := range dates {
+		responses[i] = date.ToResponse()
+	}
+
+	return c.JSON(responses)
+}
+
+// CreateDate godoc
+// @Summary Add a date
+// @Description Add a labeled significant date to a contact, with or without the year
+// @Tags dates
+// @Accept json
+// @Produce json
+// @Param id path int true "Contact ID"
+// @Param date body models.SignificantDateRequest true "Date data"
+// @Success 201 {object} models.SignificantDateResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /contacts/{id}/dates [post]
+func (h *DateHandler) CreateDate(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid contact ID",
+		})
+	}
+
+	var req models.SignificantDateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Validation failed",
+			"details": err.Error(),
+		})
+	}
+
+	date, err := h.service.CreateDate(uint(id), &req)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Contact not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create date",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(date.ToResponse())
+}
+
+// UpdateDate godoc
+// @Summary Update a date
+// @Description Replace a significant date of a contact
+// @Tags dates
+// @Accept json
+// @Produce json
+// @Param id path int true "Contact ID"
+// @Param dateId path int true "Date ID"
+// @Param date body models.SignificantDateRequest true "Date data"
+// @Success 200 {object} models.SignificantDateResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /contacts/{id}/dates/{dateId} [put]
+func (h *DateHandler) UpdateDate(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid contact ID",
+		})
+	}
+
+	dateID, err := strconv.ParseUint(c.Params("dateId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid date ID",
+		})
+	}
+
+	var req models.SignificantDateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Validation failed",
+			"details": err.Error(),
+		})
+	}
+
+	date, err := h.service.UpdateDate(uint(id), uint(dateID), &req)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Contact not found",
+			})
+		}
+		if err == services.ErrDateNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Date not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update date",
+		})
+	}
+
+	return c.JSON(date.ToResponse())
+}
+
+// DeleteDate godoc
+// @Summary Delete a date
+// @Description Remove a significant date from a contact
+// @Tags dates
+// @Accept json
+// @Produce json
+// @Param id path int true "Contact ID"
+// @Param dateId path int true "Date ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Router /contacts/{id}/dates/{dateId} [delete]
+func (h *DateHandler) DeleteDate(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid contact ID",
+		})
+	}
+
+	dateID, err := strconv.ParseUint(c.Params("dateId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid date ID",
+		})
+	}
+
+	if err := h.service.DeleteDate(uint(id), uint(dateID)); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Contact not found",
+			})
+		}
+		if err == services.ErrDateNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Date not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to delete date",
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// GetUpcomingDates godoc
+// @Summary Get upcoming dates
+// @Description List birthdays and significant dates in the next days, soonest first
+// @Tags dates
+// @Accept json
+// @Produce json
+// @Param days query int false "Days ahead, from 1 to 366" default(30)
+// @Success 200 {array} models.UpcomingDate
+// @Failure 400 {object} map[string]string
+// @Router /contacts/upcoming-dates [get]
+func (h *DateHandler) GetUpcomingDates(c *fiber.Ctx) error {
+	days, err := strconv.Atoi(c.Query("days", "30"))
+	if err != nil || days < 1 || days > 366 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "days must be a number between 1 and 366",
+		})
+	}
+
+	dates, err := h.service.UpcomingDates(time.Now(), days)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch upcoming dates",
+		})
+	}
+
+	return c.JSON(dates)
+}
+
+// GetCalendar godoc
+// @Summary Get dates calendar
+// @Description iCalendar feed with a yearly event for every birthday and significant date
+// @Tags dates
+// @Produce text/calendar
+// @Success 200 {string} string
+// @Router /contacts/calendar.ics [get]
+func (h *DateHandler) GetCalendar(c *fiber.Ctx) error {
+	dates, err := h.service.CalendarDates()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to build calendar",
+		})
+	}
+
+	c.Set(fiber.HeaderContentType, "text/calendar; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, `inline; filename="contacts.ics"`)
+	return c.SendString(renderCalendar(dates, time.Now()))
+}
@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+
+	"api-contacts-go/internal/models"
+	"api-contacts-go/internal/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// What an import does with rows whose email belongs to a contact
+const (
+	importExistingSkip   = "skip"
+	importExistingUpdate = "update"
+)
+
+// ImportContacts godoc
+// @Summary Import contacts from CSV
+// @Description Create contacts from the rows of a CSV file, sent as the "file" part of a multipart form or as the request body. The first row names the columns. Rows whose email is already in use are skipped or update that contact.
+// @Tags contacts
+// @Accept multipart/form-data
+// @Accept text/csv
+// @Produce json
+// @Param file formData file false "CSV file, when sending a form"
+// @Param delimiter query string false "Column delimiter, a character or tab" default(,)
+// @Param encoding query string false "Character set of the file" Enums(utf-8, latin1, iso-8859-1, windows-1252, cp1252) default(utf-8)
+// @Param mapping query string false "JSON object from column headers to name, email, phone, company, birthday or cf.{name}; columns named after a field by default"
+// @Param existing query string false "What to do with rows whose email is in use" Enums(skip, update) default(skip)
+// @Param dry_run query bool false "Validate every row without saving anything" default(false)
+// @Success 200 {object} models.ImportReport
+// @Failure 400 {object} map[string]string
+// @Router /contacts/import [post]
+func (h *ContactHandler) ImportContacts(c *fiber.Ctx) error {
+	delimiter, err := services.ParseImportDelimiter(c.FormValue("delimiter"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	var mapping map[string]string
+	if value := c.FormValue("mapping"); value != "" {
+		if err := json.Unmarshal([]byte(value), &mapping); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "mapping must be a JSON object from column headers to fields",
+			})
+		}
+	}
+
+	existing := c.FormValue("existing", importExistingSkip)
+	if existing != importExistingSkip && existing != importExistingUpdate {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "existing must be 'skip' or 'update'",
+		})
+	}
+
+	dryRun, err := strconv.ParseBool(c.FormValue("dry_run", "false"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "dry_run must be true or false",
+		})
+	}
+
+	// Forms carry the file in a part, otherwise it is the whole body
+	var file io.Reader = bytes.NewReader(c.Body())
+	if strings.HasPrefix(c.Get(fiber.HeaderContentType), fiber.MIMEMultipartForm) {
+		header, err := c.FormFile("file")
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "file is required",
+			})
+		}
+		part, err := header.Open()
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "file is required",
+			})
+		}
+		defer part.Close()
+		file = part
+	}
+
+	rows, ignored, err := services.ReadImport(file, services.ImportOptions{
+		Delimiter: delimiter,
+		Encoding:  c.FormValue("encoding"),
+		Mapping:   mapping,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	report := models.ImportReport{
+		DryRun:         dryRun,
+		IgnoredColumns: ignored,
+		Rows:           make([]models.ImportRowResult, 0, len(rows)),
+	}
+	if report.IgnoredColumns == nil {
+		report.IgnoredColumns = []string{}
+	}
+
+	// Emails a dry run would have created, which later rows then find in use
+	planned := map[string]bool{}
+	for _, row := range rows {
+		result := h.importRow(row, existing, dryRun, planned)
+		switch result.Status {
+		case models.ImportCreated:
+			report.Created++
+		case models.ImportUpdated:
+			report.Updated++
+		case models.ImportSkipped:
+			report.Skipped++
+		default:
+			report.Failed++
+		}
+		report.Rows = append(report.Rows, result)
+	}
+
+	return c.JSON(report)
+}
+
+// importRow creates the contact of a row, or updates or skips the one
+// already using its email, validating it as CreateContact and UpdateContact
+// do. Dry runs stop short of saving.
+func (h *ContactHandler) importRow(row services.ImportRow, existing string, dryRun bool, planned map[string]bool) models.ImportRowResult {
+	result := models.ImportRowResult{Line: row.Line}
+	fail := func(contactErr *contactError) models.ImportRowResult {
+		result.Status = models.ImportFailed
+		result.Error = contactErr.message
+		result.Details = contactErr.details
+		return result
+	}
+	const failure = "Failed to import contact"
+
+	if len(row.Values) == 0 {
+		result.Status = models.ImportSkipped
+		result.Reason = "empty row"
+		return result
+	}
+
+	raw := map[string]string{}
+	for field, value := range row.Values {
+		if name, ok := strings.CutPrefix(field, "cf."); ok {
+			raw[name] = value
+		}
+	}
+	var customFields map[string]any
+	if len(raw) > 0 {
+		parsed, err := h.customFields.ParseValues(raw)
+		if err != nil {
+			return fail(&contactError{status: fiber.StatusInternalServerError, message: failure})
+		}
+		customFields = parsed
+	}
+
+	email := row.Values["email"]
+	var id uint
+	if email != "" {
+		found, err := h.service.FindContactByEmail(email)
+		if err != nil {
+			return fail(&contactError{status: fiber.StatusInternalServerError, message: failure})
+		}
+		id = found
+	}
+	if id != 0 {
+		result.ContactID = &id
+	}
+
+	if id != 0 || planned[strings.ToLower(email)] {
+		if existing == importExistingSkip {
+			result.Status = models.ImportSkipped
+			result.Reason = "email already in use"
+			return result
+		}
+
+		req := models.UpdateContactRequest{CustomFields: customFields}
+		for field, target := range map[string]**string{"name": &req.Name, "phone": &req.Phone, "company": &req.Company, "birthday": &req.Birthday} {
+			if value, ok := row.Values[field]; ok {
+				*target = &value
+			}
+		}
+		if contactErr := h.validateUpdate(&req, failure); contactErr != nil {
+			return fail(contactErr)
+		}
+		if !dryRun {
+			if _, err := h.service.UpdateContact(id, &req); err != nil {
+				return fail(contactWriteError(err, failure))
+			}
+		}
+		result.Status = models.ImportUpdated
+		return result
+	}
+
+	req := models.CreateContactRequest{
+		Name:         row.Values["name"],
+		Email:        email,
+		Phone:        row.Values["phone"],
+		Company:      row.Values["company"],
+		Birthday:     row.Values["birthday"],
+		CustomFields: customFields,
+	}
+	if contactErr := h.validateCreate(&req, failure); contactErr != nil {
+		return fail(contactErr)
+	}
+
+	contact := newContact(&req)
+	if dryRun {
+		if err := h.service.CheckNewContact(contact); err != nil {
+			return fail(contactWriteError(err, failure))
+		}
+		planned[strings.ToLower(email)] = true
+	} else {
+		if err := h.service.CreateContact(contact); err != nil {
+			return fail(contactWriteError(err, failure))
+		}
+		result.ContactID = &contact.ID
+	}
+	result.Status = models.ImportCreated
+	return result
+}
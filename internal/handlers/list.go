@@ -0,0 +1,371 @@
+package handlers
+
+import (
+	"strconv"
+
+	"api-contacts-go/internal/models"
+	"api-contacts-go/internal/services"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type ListHandler struct {
+	service   *services.ListService
+	contacts  *services.ContactService
+	validator *validator.Validate
+}
+
+func NewListHandler(db *gorm.DB, index services.SearchIndex) *ListHandler {
+	return &ListHandler{
+		service:   services.NewListService(db),
+		contacts:  services.NewContactService(db, index),
+		validator: newValidator(),
+	}
+}
+
+// GetLists godoc
+// @Summary Get all lists
+// @Description Get paginated list of contact lists with their number of members
+// @Tags lists
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} models.PaginatedContactListResponse
+// @Router /lists [get]
+func (h *ListHandler) GetLists(c *fiber.Ctx) error {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "10"))
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	lists, total, err := h.service.GetLists(page, limit)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch lists",
+		})
+	}
+
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+
+	return c.JSON(models.PaginatedContactListResponse{
+		Data:       lists,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: totalPages,
+	})
+}
+
+// GetList godoc
+// @Summary Get list by ID
+// @Description Get a specific contact list by ID
+// @Tags lists
+// @Accept json
+// @Produce json
+// @Param id path int true "List ID"
+// @Success 200 {object} models.ContactList
+// @Failure 404 {object} map[string]string
+// @Router /lists/{id} [get]
+func (h *ListHandler) GetList(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid list ID",
+		})
+	}
+
+	list, err := h.service.GetList(uint(id))
+	if err != nil {
+		if err == services.ErrListNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "List not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch list",
+		})
+	}
+
+	return c.JSON(list)
+}
+
+// GetListMembers godoc
+// @Summary Get list members
+// @Description Get paginated list of the contacts in a list
+// @Tags lists
+// @Accept json
+// @Produce json
+// @Param id path int true "List ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} models.PaginatedResponse
+// @Failure 404 {object} map[string]string
+// @Router /lists/{id}/members [get]
+func (h *ListHandler) GetListMembers(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid list ID",
+		})
+	}
+
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "10"))
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	list, err := h.service.GetList(uint(id))
+	if err != nil {
+		if err == services.ErrListNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "List not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch members",
+		})
+	}
+
+	contacts, total, err := h.contacts.GetContacts(page, limit, services.ContactFilter{ListID: &list.ID})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch members",
+		})
+	}
+
+	// Convert to response format
+	contactResponses := []models.ContactResponse{}
+	for _, contact := range contacts {
+		contactResponses = append(contactResponses, contact.ToResponse())
+	}
+
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+
+	return c.JSON(models.PaginatedResponse{
+		Data:       contactResponses,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: totalPages,
+	})
+}
+
+// CreateList godoc
+// @Summary Create a new list
+// @Description Create a new, empty contact list
+// @Tags lists
+// @Accept json
+// @Produce json
+// @Param list body models.CreateContactListRequest true "List data"
+// @Success 201 {object} models.ContactList
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /lists [post]
+func (h *ListHandler) CreateList(c *fiber.Ctx) error {
+	var req models.CreateContactListRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Validation failed",
+			"details": err.Error(),
+		})
+	}
+
+	list, err := h.service.CreateList(&req)
+	if err != nil {
+		if err == services.ErrListExists {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": "List already exists",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create list",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(list)
+}
+
+// UpdateList godoc
+// @Summary Update a list
+// @Description Rename a contact list or change its description
+// @Tags lists
+// @Accept json
+// @Produce json
+// @Param id path int true "List ID"
+// @Param list body models.UpdateContactListRequest true "List data"
+// @Success 200 {object} models.ContactList
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /lists/{id} [put]
+func (h *ListHandler) UpdateList(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid list ID",
+		})
+	}
+
+	var req models.UpdateContactListRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Validation failed",
+			"details": err.Error(),
+		})
+	}
+
+	list, err := h.service.UpdateList(uint(id), &req)
+	if err != nil {
+		if err == services.ErrListNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "List not found",
+			})
+		}
+		if err == services.ErrListExists {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": "List already exists",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update list",
+		})
+	}
+
+	return c.JSON(list)
+}
+
+// DeleteList godoc
+// @Summary Delete a list
+// @Description Delete a contact list, leaving its members untouched
+// @Tags lists
+// @Accept json
+// @Produce json
+// @Param id path int true "List ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Router /lists/{id} [delete]
+func (h *ListHandler) DeleteList(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid list ID",
+		})
+	}
+
+	if err := h.service.DeleteList(uint(id)); err != nil {
+		if err == services.ErrListNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "List not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to delete list",
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// AddListMembers godoc
+// @Summary Add list members
+// @Description Add contacts to a list in bulk, ignoring the ones already in it
+// @Tags lists
+// @Accept json
+// @Produce json
+// @Param id path int true "List ID"
+// @Param members body models.ListMembersRequest true "Contact IDs"
+// @Success 200 {object} models.ContactList
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /lists/{id}/members [post]
+func (h *ListHandler) AddListMembers(c *fiber.Ctx) error {
+	return h.changeMembers(c, h.service.AddMembers)
+}
+
+// RemoveListMembers godoc
+// @Summary Remove list members
+// @Description Remove contacts from a list in bulk
+// @Tags lists
+// @Accept json
+// @Produce json
+// @Param id path int true "List ID"
+// @Param members body models.ListMembersRequest true "Contact IDs"
+// @Success 200 {object} models.ContactList
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /lists/{id}/members [delete]
+func (h *ListHandler) RemoveListMembers(c *fiber.Ctx) error {
+	return h.changeMembers(c, h.service.RemoveMembers)
+}
+
+// changeMembers parses a bulk membership request and applies it with change
+func (h *ListHandler) changeMembers(c *fiber.Ctx, change func(uint, []uint) (*models.ContactList, error)) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid list ID",
+		})
+	}
+
+	var req models.ListMembersRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Validation failed",
+			"details": err.Error(),
+		})
+	}
+
+	list, err := change(uint(id), req.ContactIDs)
+	if err != nil {
+		if err == services.ErrListNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "List not found",
+			})
+		}
+		if missing, ok := err.(*services.MissingContactsError); ok {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":       "Some contacts do not exist",
+				"contact_ids": missing.IDs,
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update list members",
+		})
+	}
+
+	return c.JSON(list)
+}
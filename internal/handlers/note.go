@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"strconv"
+
+	"api-contacts-go/internal/models"
+	"api-contacts-go/internal/services"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type NoteHandler struct {
+	service   *services.NoteService
+	validator *validator.Validate
+}
+
+func NewNoteHandler(db *gorm.DB) *NoteHandler {
+	return &NoteHandler{
+		service:   services.NewNoteService(db),
+		validator: newValidator(),
+	}
+}
+
+// GetNotes godoc
+// @Summary Get contact notes
+// @Description Get paginated notes of a contact, pinned notes first
+// @Tags notes
+// @Accept json
+// @Produce json
+// @Param id path int true "Contact ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} models.PaginatedNoteResponse
+// @Failure 404 {object} map[string]string
+// @Router /contacts/{id}/notes [get]
+func (h *NoteHandler) GetNotes(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid contact ID",
+		})
+	}
+
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "10"))
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	notes, total, err := h.service.GetNotes(uint(id), page, limit)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Contact not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch notes",
+		})
+	}
+
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+
+	return c.JSON(models.PaginatedNoteResponse{
+		Data:       notes,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: totalPages,
+	})
+}
+
+// CreateNote godoc
+// @Summary Add a note
+// @Description Add a markdown note to a contact
+// @Tags notes
+// @Accept json
+// @Produce json
+// @Param id path int true "Contact ID"
+// @Param note body models.CreateNoteRequest true "Note data"
+// @Success 201 {object} models.Note
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /contacts/{id}/notes [post]
+func (h *NoteHandler) CreateNote(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid contact ID",
+		})
+	}
+
+	var req models.CreateNoteRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Validation failed",
+			"details": err.Error(),
+		})
+	}
+
+	note, err := h.service.CreateNote(uint(id), &req)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Contact not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create note",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(note)
+}
+
+// UpdateNote godoc
+// @Summary Update a note
+// @Description Edit the body of a note or pin and unpin it
+// @Tags notes
+// @Accept json
+// @Produce json
+// @Param id path int true "Contact ID"
+// @Param noteId path int true "Note ID"
+// @Param note body models.UpdateNoteRequest true "Note changes"
+// @Success 200 {object} models.Note
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /contacts/{id}/notes/{noteId} [put]
+func (h *NoteHandler) UpdateNote(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid contact ID",
+		})
+	}
+
+	noteID, err := strconv.ParseUint(c.Params("noteId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid note ID",
+		})
+	}
+
+	var req models.UpdateNoteRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Validation failed",
+			"details": err.Error(),
+		})
+	}
+
+	note, err := h.service.UpdateNote(uint(id), uint(noteID), &req)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Contact not found",
+			})
+		}
+		if err == services.ErrNoteNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Note not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update note",
+		})
+	}
+
+	return c.JSON(note)
+}
+
+// DeleteNote godoc
+// @Summary Delete a note
+// @Description Delete a note from a contact
+// @Tags notes
+// @Accept json
+// @Produce json
+// @Param id path int true "Contact ID"
+// @Param noteId path int true "Note ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Router /contacts/{id}/notes/{noteId} [delete]
+func (h *NoteHandler) DeleteNote(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid contact ID",
+		})
+	}
+
+	noteID, err := strconv.ParseUint(c.Params("noteId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid note ID",
+		})
+	}
+
+	if err := h.service.DeleteNote(uint(id), uint(noteID)); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Contact not found",
+			})
+		}
+		if err == services.ErrNoteNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Note not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to delete note",
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
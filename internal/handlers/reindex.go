@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"api-contacts-go/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Reindex godoc
+// @Summary Rebuild the search index
+// @Description Rebuild the search index from the contacts in the database while the API keeps serving; searches use the current index until the new one is ready
+// @Tags contacts
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.ReindexResponse
+// @Failure 500 {object} map[string]string
+// @Router /contacts/reindex [post]
+func (h *ContactHandler) Reindex(c *fiber.Ctx) error {
+	indexed, err := h.service.Reindex()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to rebuild search index",
+		})
+	}
+
+	return c.JSON(models.ReindexResponse{Indexed: indexed})
+}
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"strconv"
+
+	"api-contacts-go/internal/models"
+	"api-contacts-go/internal/services"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type RelationshipHandler struct {
+	service   *services.RelationshipService
+	validator *validator.Validate
+}
+
+func NewRelationshipHandler(db *gorm.DB) *RelationshipHandler {
+	return &RelationshipHandler{
+		service:   services.NewRelationshipService(db),
+		validator: newValidator(),
+	}
+}
+
+// GetRelationships godoc
+// @Summary Get contact relationships
+// @Description Get the contacts related to a contact, grouped by relationship type
+// @Tags relationships
+// @Accept json
+// @Produce json
+// @Param id path int true "Contact ID"
+// @Success 200 {array} models.RelationshipResponse
+// @Failure 404 {object} map[string]string
+// @Router /contacts/{id}/relationships [get]
+func (h *RelationshipHandler) GetRelationships(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid contact ID",
+		})
+	}
+
+	relationships, err := h.service.GetRelationships(uint(id))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Contact not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch relationships",
+		})
+	}
+
+	responses := make([]models.RelationshipResponse, len(relationships))
+	for i, relationship := range relationships {
+		responses[i] = relationship.ToResponse()
+	}
+
+	return c.JSON(responses)
+}
+
+// CreateRelationship godoc
+// @Summary Relate two contacts
+// @Description Link a contact to another one, optionally storing the inverse link as well
+// @Tags relationships
+// @Accept json
+// @Produce json
+// @Param id path int true "Contact ID"
+// @Param relationship body models.CreateRelationshipRequest true "Relationship data"
+// @Success 201 {object} models.RelationshipResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /contacts/{id}/relationships [post]
+func (h *RelationshipHandler) CreateRelationship(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid contact ID",
+		})
+	}
+
+	var req models.CreateRelationshipRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Validation failed",
+			"details": err.Error(),
+		})
+	}
+
+	relationship, err := h.service.CreateRelationship(uint(id), &req)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Contact not found",
+			})
+		}
+		if err == services.ErrRelatedContactNotFound || err == services.ErrSelfRelationship || err == services.ErrRelationshipCycle {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		if err == services.ErrRelationshipExists {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": "Relationship already exists",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create relationship",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(relationship.ToResponse())
+}
+
+// DeleteRelationship godoc
+// @Summary Delete a relationship
+// @Description Remove a relationship from a contact, along with its inverse link
+// @Tags relationships
+// @Accept json
+// @Produce json
+// @Param id path int true "Contact ID"
+// @Param relationshipId path int true "Relationship ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Router /contacts/{id}/relationships/{relationshipId} [delete]
+func (h *RelationshipHandler) DeleteRelationship(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid contact ID",
+		})
+	}
+
+	relationshipID, err := strconv.ParseUint(c.Params("relationshipId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid relationship ID",
+		})
+	}
+
+	if err := h.service.DeleteRelationship(uint(id), uint(relationshipID)); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Contact not found",
+			})
+		}
+		if err == services.ErrRelationshipNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Relationship not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to delete relationship",
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -1,19 +1,96 @@
 package handlers
 
 import (
+	"api-contacts-go/internal/services"
+
 	"github.com/gofiber/fiber/v2"
 	"gorm.io/gorm"
 )
 
-func SetupRoutes(router fiber.Router, db *gorm.DB) {
-	contactHandler := NewContactHandler(db)
+// SetupRoutes registers the API, searching contacts with index, or with the
+// database alone when index is nil
+func SetupRoutes(router fiber.Router, db *gorm.DB, index services.SearchIndex) {
+	contactHandler := NewContactHandler(db, index)
+	tagHandler := NewTagHandler(db, index)
+	addressHandler := NewAddressHandler(db)
+	customFieldHandler := NewCustomFieldHandler(db)
+	noteHandler := NewNoteHandler(db)
+	companyHandler := NewCompanyHandler(db, index)
+	relationshipHandler := NewRelationshipHandler(db)
+	listHandler := NewListHandler(db, index)
+	smartListHandler := NewSmartListHandler(db, index)
+	dateHandler := NewDateHandler(db)
 
 	// Contact routes
 	contacts := router.Group("/contacts")
 	contacts.Get("/", contactHandler.GetContacts)
 	contacts.Get("/search", contactHandler.SearchContacts)
+	contacts.Get("/autocomplete", contactHandler.Autocomplete)
+	contacts.Get("/upcoming-dates", dateHandler.GetUpcomingDates)
+	contacts.Get("/calendar.ics", dateHandler.GetCalendar)
 	contacts.Get("/:id", contactHandler.GetContact)
 	contacts.Post("/", contactHandler.CreateContact)
+	contacts.Post("/import", contactHandler.ImportContacts)
+	contacts.Post("/reindex", contactHandler.Reindex)
 	contacts.Put("/:id", contactHandler.UpdateContact)
 	contacts.Delete("/:id", contactHandler.DeleteContact)
+	contacts.Post("/:id/selections", contactHandler.SelectContact)
+	contacts.Get("/:id/tags", tagHandler.GetContactTags)
+	contacts.Post("/:id/tags", tagHandler.AddContactTags)
+	contacts.Delete("/:id/tags/:tag", tagHandler.RemoveContactTag)
+	contacts.Get("/:id/addresses", addressHandler.GetAddresses)
+	contacts.Post("/:id/addresses", addressHandler.CreateAddress)
+	contacts.Put("/:id/addresses/:addressId", addressHandler.UpdateAddress)
+	contacts.Delete("/:id/addresses/:addressId", addressHandler.DeleteAddress)
+	contacts.Get("/:id/notes", noteHandler.GetNotes)
+	contacts.Post("/:id/notes", noteHandler.CreateNote)
+	contacts.Put("/:id/notes/:noteId", noteHandler.UpdateNote)
+	contacts.Delete("/:id/notes/:noteId", noteHandler.DeleteNote)
+	contacts.Get("/:id/dates", dateHandler.GetDates)
+	contacts.Post("/:id/dates", dateHandler.CreateDate)
+	contacts.Put("/:id/dates/:dateId", dateHandler.UpdateDate)
+	contacts.Delete("/:id/dates/:dateId", dateHandler.DeleteDate)
+	contacts.Get("/:id/relationships", relationshipHandler.GetRelationships)
+	contacts.Post("/:id/relationships", relationshipHandler.CreateRelationship)
+	contacts.Delete("/:id/relationships/:relationshipId", relationshipHandler.DeleteRelationship)
+
+	// Tag routes
+	router.Get("/tags", tagHandler.GetTags)
+
+	// Company routes
+	companies := router.Group("/companies")
+	companies.Get("/", companyHandler.GetCompanies)
+	companies.Get("/:id", companyHandler.GetCompany)
+	companies.Get("/:id/contacts", companyHandler.GetCompanyContacts)
+	companies.Post("/", companyHandler.CreateCompany)
+	companies.Put("/:id", companyHandler.UpdateCompany)
+	companies.Delete("/:id", companyHandler.DeleteCompany)
+
+	// List routes
+	lists := router.Group("/lists")
+	lists.Get("/", listHandler.GetLists)
+	lists.Get("/:id", listHandler.GetList)
+	lists.Get("/:id/members", listHandler.GetListMembers)
+	lists.Post("/", listHandler.CreateList)
+	lists.Put("/:id", listHandler.UpdateList)
+	lists.Delete("/:id", listHandler.DeleteList)
+	lists.Post("/:id/members", listHandler.AddListMembers)
+	lists.Delete("/:id/members", listHandler.RemoveListMembers)
+
+	// Smart list routes
+	smartLists := router.Group("/smart-lists")
+	smartLists.Get("/", smartListHandler.GetSmartLists)
+	smartLists.Get("/:id", smartListHandler.GetSmartList)
+	smartLists.Get("/:id/contacts", smartListHandler.GetSmartListContacts)
+	smartLists.Post("/", smartListHandler.CreateSmartList)
+	smartLists.Put("/:id", smartListHandler.UpdateSmartList)
+	smartLists.Delete("/:id", smartListHandler.DeleteSmartList)
+
+	// Custom field routes
+	customFields := router.Group("/custom-fields")
+	customFields.Get("/", customFieldHandler.GetCustomFields)
+	customFields.Get("/:id", customFieldHandler.GetCustomField)
+	customFields.Post("/", customFieldHandler.CreateCustomField)
+	customFields.Put("/:id", customFieldHandler.UpdateCustomField)
+	customFields.Delete("/:id", customFieldHandler.DeleteCustomField)
 }
@@ -0,0 +1,316 @@
+package handlers
+
+import (
+	"strconv"
+
+	"api-contacts-go/internal/models"
+	"api-contacts-go/internal/services"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type SmartListHandler struct {
+	service   *services.SmartListService
+	validator *validator.Validate
+}
+
+func NewSmartListHandler(db *gorm.DB, index services.SearchIndex) *SmartListHandler {
+	return &SmartListHandler{
+		service:   services.NewSmartListService(db, index),
+		validator: newValidator(),
+	}
+}
+
+// GetSmartLists godoc
+// @Summary Get all smart lists
+// @Description Get paginated list of smart lists, optionally evaluating each one to count its current members
+// @Tags smart-lists
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param counts query bool false "Count the members of each list" default(false)
+// @Success 200 {object} models.PaginatedSmartListResponse
+// @Failure 400 {object} map[string]string
+// @Router /smart-lists [get]
+func (h *SmartListHandler) GetSmartLists(c *fiber.Ctx) error {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "10"))
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	// Counting evaluates every list of the page, so it is left to clients
+	// that show the counts
+	counts, err := strconv.ParseBool(c.Query("counts", "false"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "counts must be true or false",
+		})
+	}
+
+	lists, total, err := h.service.GetSmartLists(page, limit, counts)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch smart lists",
+		})
+	}
+
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+
+	return c.JSON(models.PaginatedSmartListResponse{
+		Data:       lists,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: totalPages,
+	})
+}
+
+// GetSmartList godoc
+// @Summary Get smart list by ID
+// @Description Get a specific smart list by ID
+// @Tags smart-lists
+// @Accept json
+// @Produce json
+// @Param id path int true "Smart list ID"
+// @Success 200 {object} models.SmartList
+// @Failure 404 {object} map[string]string
+// @Router /smart-lists/{id} [get]
+func (h *SmartListHandler) GetSmartList(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid smart list ID",
+		})
+	}
+
+	list, err := h.service.GetSmartList(uint(id))
+	if err != nil {
+		if err == services.ErrSmartListNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Smart list not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch smart list",
+		})
+	}
+
+	return c.JSON(list)
+}
+
+// GetSmartListContacts godoc
+// @Summary Get smart list contacts
+// @Description Evaluate a smart list and get the paginated contacts it currently matches
+// @Tags smart-lists
+// @Accept json
+// @Produce json
+// @Param id path int true "Smart list ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} models.PaginatedResponse
+// @Failure 404 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Router /smart-lists/{id}/contacts [get]
+func (h *SmartListHandler) GetSmartListContacts(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid smart list ID",
+		})
+	}
+
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "10"))
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	contacts, total, err := h.service.GetContacts(uint(id), page, limit)
+	if err != nil {
+		if err == services.ErrSmartListNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Smart list not found",
+			})
+		}
+		// Saved definitions can break when custom fields or companies are removed
+		if _, ok := err.(*services.SmartListError); ok {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+				"error":   "Smart list definition is no longer valid",
+				"details": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch contacts",
+		})
+	}
+
+	// Convert to response format
+	contactResponses := []models.ContactResponse{}
+	for _, contact := range contacts {
+		contactResponses = append(contactResponses, contact.ToResponse())
+	}
+
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+
+	return c.JSON(models.PaginatedResponse{
+		Data:       contactResponses,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: totalPages,
+	})
+}
+
+// CreateSmartList godoc
+// @Summary Create a new smart list
+// @Description Save a search definition as a smart list
+// @Tags smart-lists
+// @Accept json
+// @Produce json
+// @Param list body models.CreateSmartListRequest true "Smart list data"
+// @Success 201 {object} models.SmartList
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /smart-lists [post]
+func (h *SmartListHandler) CreateSmartList(c *fiber.Ctx) error {
+	var req models.CreateSmartListRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Validation failed",
+			"details": err.Error(),
+		})
+	}
+
+	list, err := h.service.CreateSmartList(&req)
+	if err != nil {
+		if _, ok := err.(*services.SmartListError); ok {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "Validation failed",
+				"details": err.Error(),
+			})
+		}
+		if err == services.ErrSmartListExists {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": "Smart list already exists",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create smart list",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(list)
+}
+
+// UpdateSmartList godoc
+// @Summary Update a smart list
+// @Description Rename a smart list or replace its definition
+// @Tags smart-lists
+// @Accept json
+// @Produce json
+// @Param id path int true "Smart list ID"
+// @Param list body models.UpdateSmartListRequest true "Smart list data"
+// @Success 200 {object} models.SmartList
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /smart-lists/{id} [put]
+func (h *SmartListHandler) UpdateSmartList(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid smart list ID",
+		})
+	}
+
+	var req models.UpdateSmartListRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Validation failed",
+			"details": err.Error(),
+		})
+	}
+
+	list, err := h.service.UpdateSmartList(uint(id), &req)
+	if err != nil {
+		if err == services.ErrSmartListNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Smart list not found",
+			})
+		}
+		if _, ok := err.(*services.SmartListError); ok {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "Validation failed",
+				"details": err.Error(),
+			})
+		}
+		if err == services.ErrSmartListExists {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": "Smart list already exists",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update smart list",
+		})
+	}
+
+	return c.JSON(list)
+}
+
+// DeleteSmartList godoc
+// @Summary Delete a smart list
+// @Description Delete a smart list definition
+// @Tags smart-lists
+// @Accept json
+// @Produce json
+// @Param id path int true "Smart list ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Router /smart-lists/{id} [delete]
+func (h *SmartListHandler) DeleteSmartList(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid smart list ID",
+		})
+	}
+
+	if err := h.service.DeleteSmartList(uint(id)); err != nil {
+		if err == services.ErrSmartListNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Smart list not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to delete smart list",
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
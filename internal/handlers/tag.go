@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"net/url"
+	"strconv"
+
+	"api-contacts-go/internal/models"
+	"api-contacts-go/internal/services"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type TagHandler struct {
+	service   *services.TagService
+	validator *validator.Validate
+}
+
+func NewTagHandler(db *gorm.DB, index services.SearchIndex) *TagHandler {
+	return &TagHandler{
+		service:   services.NewTagService(db, index),
+		validator: newValidator(),
+	}
+}
+
+// GetTags godoc
+// @Summary List tags
+// @Description List all tags with the number of contacts using each one
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.TagResponse
+// @Router /tags [get]
+func (h *TagHandler) GetTags(c *fiber.Ctx) error {
+	tags, err := h.service.ListTags()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch tags",
+		})
+	}
+
+	if tags == nil {
+		tags = []models.TagResponse{}
+	}
+
+	return c.JSON(tags)
+}
+
+// GetContactTags godoc
+// @Summary Get contact tags
+// @Description Get the tags attached to a contact
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param id path int true "Contact ID"
+// @Success 200 {array} models.Tag
+// @Failure 404 {object} map[string]string
+// @Router /contacts/{id}/tags [get]
+func (h *TagHandler) GetContactTags(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid contact ID",
+		})
+	}
+
+	tags, err := h.service.GetContactTags(uint(id))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Contact not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch tags",
+		})
+	}
+
+	return c.JSON(tags)
+}
+
+// AddContactTags godoc
+// @Summary Tag a contact
+// @Description Attach tags to a contact, creating unknown tags on the fly
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param id path int true "Contact ID"
+// @Param tags body models.TagsRequest true "Tag names"
+// @Success 200 {array} models.Tag
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /contacts/{id}/tags [post]
+func (h *TagHandler) AddContactTags(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid contact ID",
+		})
+	}
+
+	var req models.TagsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Validation failed",
+			"details": err.Error(),
+		})
+	}
+
+	tags, err := h.service.AddContactTags(uint(id), req.Tags)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Contact not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to tag contact",
+		})
+	}
+
+	return c.JSON(tags)
+}
+
+// RemoveContactTag godoc
+// @Summary Untag a contact
+// @Description Detach a tag from a contact
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param id path int true "Contact ID"
+// @Param tag path string true "Tag name"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Router /contacts/{id}/tags/{tag} [delete]
+func (h *TagHandler) RemoveContactTag(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid contact ID",
+		})
+	}
+
+	name, err := url.PathUnescape(c.Params("tag"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid tag name",
+		})
+	}
+
+	if err := h.service.RemoveContactTag(uint(id), name); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Contact not found",
+			})
+		}
+		if err == services.ErrTagNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Tag not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to untag contact",
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"regexp"
+
+	"api-contacts-go/internal/models"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// fieldKeyPattern restricts custom field names to snake_case identifiers
+var fieldKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// newValidator builds the request validator shared by the handlers, with
+// the rules that go beyond the built-in tags registered on it
+func newValidator() *validator.Validate {
+	validate := validator.New()
+
+	validate.RegisterValidation("field_key", func(fl validator.FieldLevel) bool {
+		return fieldKeyPattern.MatchString(fl.Field().String())
+	})
+
+	// Dates written as YYYY-MM-DD or --MM-DD, empty clears a birthday
+	validate.RegisterValidation("partial_date", func(fl validator.FieldLevel) bool {
+		value := fl.Field().String()
+		if value == "" {
+			return true
+		}
+		_, err := models.ParsePartialDate(value)
+		return err == nil
+	})
+
+	validate.RegisterStructValidation(func(sl validator.StructLevel) {
+		req := sl.Current().Interface().(models.AddressRequest)
+		if !req.HasValidPostalCode() {
+			sl.ReportError(req.PostalCode, "PostalCode", "postal_code", "cep", "")
+		}
+	}, models.AddressRequest{})
+
+	return validate
+}
@@ -0,0 +1,81 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// cepPattern matches a Brazilian postal code with or without the hyphen
+var cepPattern = regexp.MustCompile(`^\d{5}-?\d{3}$`)
+
+type Address struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	ContactID  uint      `json:"-" gorm:"index;not null"`
+	Label      string    `json:"label" gorm:"size:20;not null;default:other"`
+	Street     string    `json:"street" gorm:"size:200;not null"`
+	Number     string    `json:"number" gorm:"size:20"`
+	Complement string    `json:"complement" gorm:"size:100"`
+	District   string    `json:"district" gorm:"size:100"`
+	City       string    `json:"city" gorm:"size:100;not null"`
+	State      string    `json:"state" gorm:"size:50"`
+	PostalCode string    `json:"postal_code" gorm:"size:20;not null"`
+	Country    string    `json:"country" gorm:"size:2;not null"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type AddressRequest struct {
+	Label      string `json:"label" validate:"omitempty,oneof=home work billing shipping other"`
+	Street     string `json:"street" validate:"required,max=200"`
+	Number     string `json:"number" validate:"omitempty,max=20"`
+	Complement string `json:"complement" validate:"omitempty,max=100"`
+	District   string `json:"district" validate:"omitempty,max=100"`
+	City       string `json:"city" validate:"required,max=100"`
+	State      string `json:"state" validate:"omitempty,max=50"`
+	PostalCode string `json:"postal_code" validate:"required,max=20"`
+	Country    string `json:"country" validate:"required,iso3166_1_alpha2"`
+}
+
+// Normalize trims the request and puts country codes and CEPs in their canonical form
+func (r *AddressRequest) Normalize() {
+	r.Label = strings.ToLower(strings.TrimSpace(r.Label))
+	r.Street = strings.TrimSpace(r.Street)
+	r.Number = strings.TrimSpace(r.Number)
+	r.Complement = strings.TrimSpace(r.Complement)
+	r.District = strings.TrimSpace(r.District)
+	r.City = strings.TrimSpace(r.City)
+	r.State = strings.TrimSpace(r.State)
+	r.PostalCode = strings.TrimSpace(r.PostalCode)
+	r.Country = strings.ToUpper(strings.TrimSpace(r.Country))
+
+	if r.Country == "BR" && cepPattern.MatchString(r.PostalCode) {
+		digits := strings.ReplaceAll(r.PostalCode, "-", "")
+		r.PostalCode = digits[:5] + "-" + digits[5:]
+	}
+}
+
+// HasValidPostalCode reports whether the postal code fits the country's format.
+// Only Brazilian CEPs are checked, other countries accept any value.
+func (r *AddressRequest) HasValidPostalCode() bool {
+	if r.Country == "BR" {
+		return cepPattern.MatchString(r.PostalCode)
+	}
+	return true
+}
+
+// Apply copies the request fields onto an address
+func (r *AddressRequest) Apply(address *Address) {
+	address.Label = r.Label
+	if address.Label == "" {
+		address.Label = LabelOther
+	}
+	address.Street = r.Street
+	address.Number = r.Number
+	address.Complement = r.Complement
+	address.District = r.District
+	address.City = r.City
+	address.State = r.State
+	address.PostalCode = r.PostalCode
+	address.Country = r.Country
+}
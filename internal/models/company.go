@@ -0,0 +1,57 @@
+package models
+
+import (
+	"strings"
+	"time"
+	"unicode"
+
+	"gorm.io/gorm"
+)
+
+type Company struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	Name           string    `json:"name" gorm:"size:100;not null"`
+	NormalizedName string    `json:"-" gorm:"uniqueIndex;size:100;not null"`
+	SortName       string    `json:"-" gorm:"size:100;index;not null;default:''"`
+	Website        string    `json:"website" gorm:"size:255"`
+	ContactCount   int64     `json:"contact_count" gorm:"->;-:migration"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// BeforeSave keeps the accent-insensitive sort key in sync with the name
+func (c *Company) BeforeSave(tx *gorm.DB) error {
+	c.SortName = FoldText(c.Name)
+	return nil
+}
+
+type CreateCompanyRequest struct {
+	Name    string `json:"name" validate:"required,min=1,max=100"`
+	Website string `json:"website" validate:"omitempty,url,max=255"`
+}
+
+type UpdateCompanyRequest struct {
+	Name    *string `json:"name,omitempty" validate:"omitempty,min=1,max=100"`
+	Website *string `json:"website,omitempty" validate:"omitempty,url,max=255"`
+}
+
+type PaginatedCompanyResponse struct {
+	Data       []Company `json:"data"`
+	Total      int64     `json:"total"`
+	Page       int       `json:"page"`
+	Limit      int       `json:"limit"`
+	TotalPages int       `json:"total_pages"`
+}
+
+// NormalizeCompanyName reduces a company name to lowercase letters and digits
+// so that "Tech Corp", "TechCorp" and "tech corp" are the same company.
+// Migration 000007 applies the same rule in SQL when back-filling companies.
+func NormalizeCompanyName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -1,62 +1,318 @@
 package models
 
 import (
+	"encoding/json"
+	"slices"
 	"time"
 
 	"gorm.io/gorm"
 )
 
 type Contact struct {
-	ID        uint           `json:"id" gorm:"primaryKey"`
-	Name      string         `json:"name" gorm:"not null" validate:"required,min=2,max=100"`
-	Email     string         `json:"email" gorm:"uniqueIndex;not null" validate:"required,email"`
-	Phone     string         `json:"phone" gorm:"size:20" validate:"omitempty,min=10,max=20"`
-	Company   string         `json:"company" gorm:"size:100" validate:"omitempty,max=100"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+	ID             uint              `json:"id" gorm:"primaryKey"`
+	Name           string            `json:"name" gorm:"not null" validate:"required,min=2,max=100"`
+	Email          string            `json:"email" gorm:"uniqueIndex;not null" validate:"required,email"`
+	Phone          string            `json:"phone" gorm:"size:20" validate:"omitempty,min=10,max=20"`
+	Company        string            `json:"company" gorm:"size:100" validate:"omitempty,max=100"`
+	CompanyID      *uint             `json:"company_id" gorm:"index"`
+	NameKey        string            `json:"-" gorm:"size:100;index;not null;default:''"`
+	CompanyKey     string            `json:"-" gorm:"size:100;not null;default:''"`
+	PhoneticKey    string            `json:"-" gorm:"size:200;index;not null;default:''"`
+	MethodsKey     string            `json:"-" gorm:"type:text;not null;default:''"`
+	CustomFields   JSONMap           `json:"custom_fields"`
+	BirthYear      *int              `json:"-"`
+	BirthMonth     *int              `json:"-"`
+	BirthDay       *int              `json:"-"`
+	SelectionCount int64             `json:"-" gorm:"not null;default:0"`
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt    `json:"-" gorm:"index"`
+	Tags           []Tag             `json:"tags,omitempty" gorm:"many2many:contact_tags;"`
+	Emails         []ContactEmail    `json:"emails,omitempty"`
+	Phones         []ContactPhone    `json:"phones,omitempty"`
+	Addresses      []Address         `json:"addresses,omitempty"`
+	Lists          []ContactList     `json:"lists,omitempty" gorm:"many2many:contact_list_members;"`
+	Dates          []SignificantDate `json:"dates,omitempty"`
+	NoteCount      int64             `json:"-" gorm:"-"`
+	PinnedNote     *Note             `json:"-" gorm:"-"`
+	Relationships  []Relationship    `json:"-" gorm:"-"`
+	Highlights     []Highlight       `json:"-" gorm:"-"`
+	Score          *float64          `json:"-" gorm:"->;-:migration"`
 }
 
 type CreateContactRequest struct {
-	Name    string `json:"name" validate:"required,min=2,max=100"`
-	Email   string `json:"email" validate:"required,email"`
-	Phone   string `json:"phone" validate:"omitempty,min=10,max=20"`
-	Company string `json:"company" validate:"omitempty,max=100"`
+	Name         string              `json:"name" validate:"required,min=2,max=100"`
+	Email        string              `json:"email" validate:"required_without=Emails,omitempty,email"`
+	Phone        string              `json:"phone" validate:"omitempty,min=10,max=20"`
+	Company      string              `json:"company" validate:"omitempty,max=100"`
+	CompanyID    *uint               `json:"company_id,omitempty"`
+	Birthday     string              `json:"birthday,omitempty" validate:"omitempty,partial_date"`
+	Emails       []ContactEmailInput `json:"emails,omitempty" validate:"omitempty,dive"`
+	Phones       []ContactPhoneInput `json:"phones,omitempty" validate:"omitempty,dive"`
+	CustomFields map[string]any      `json:"custom_fields,omitempty"`
 }
 
 type UpdateContactRequest struct {
-	Name    *string `json:"name,omitempty" validate:"omitempty,min=2,max=100"`
-	Email   *string `json:"email,omitempty" validate:"omitempty,email"`
-	Phone   *string `json:"phone,omitempty" validate:"omitempty,min=10,max=20"`
-	Company *string `json:"company,omitempty" validate:"omitempty,max=100"`
+	Name         *string              `json:"name,omitempty" validate:"omitempty,min=2,max=100"`
+	Email        *string              `json:"email,omitempty" validate:"omitempty,email"`
+	Phone        *string              `json:"phone,omitempty" validate:"omitempty,min=10,max=20"`
+	Company      *string              `json:"company,omitempty" validate:"omitempty,max=100"`
+	CompanyID    *uint                `json:"company_id,omitempty"`
+	Birthday     *string              `json:"birthday,omitempty" validate:"omitempty,partial_date"`
+	Emails       *[]ContactEmailInput `json:"emails,omitempty" validate:"omitempty,min=1,dive"`
+	Phones       *[]ContactPhoneInput `json:"phones,omitempty" validate:"omitempty,dive"`
+	CustomFields map[string]any       `json:"custom_fields,omitempty"`
 }
 
 type ContactResponse struct {
-	ID        uint      `json:"id"`
-	Name      string    `json:"name"`
-	Email     string    `json:"email"`
-	Phone     string    `json:"phone"`
-	Company   string    `json:"company"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID            uint                      `json:"id"`
+	Name          string                    `json:"name"`
+	Email         string                    `json:"email"`
+	Phone         string                    `json:"phone"`
+	Company       string                    `json:"company"`
+	CompanyID     *uint                     `json:"company_id"`
+	Birthday      *string                   `json:"birthday"`
+	Dates         []SignificantDateResponse `json:"dates"`
+	Emails        []ContactEmailResponse    `json:"emails"`
+	Phones        []ContactPhoneResponse    `json:"phones"`
+	Addresses     []Address                 `json:"addresses"`
+	Tags          []string                  `json:"tags"`
+	Lists         []ContactListSummary      `json:"lists"`
+	CustomFields  map[string]any            `json:"custom_fields"`
+	NoteCount     int64                     `json:"note_count"`
+	PinnedNote    *Note                     `json:"pinned_note"`
+	Score         *float64                  `json:"score,omitempty"`
+	Relationships *[]RelationshipResponse   `json:"relationships,omitempty"`
+	Highlights    *[]Highlight              `json:"highlights,omitempty"`
+	CreatedAt     time.Time                 `json:"created_at"`
+	UpdatedAt     time.Time                 `json:"updated_at"`
+
+	// fields limits the rendered keys, see WithFields
+	fields []string
+}
+
+// ContactResponseFields are the keys of a rendered ContactResponse, which
+// sparse fieldsets choose from
+var ContactResponseFields = []string{
+	"id", "name", "email", "phone", "company", "company_id", "birthday", "dates", "emails", "phones",
+	"addresses", "tags", "lists", "custom_fields", "note_count", "pinned_note", "score", "created_at", "updated_at",
+}
+
+// WithFields renders only the given keys of the response
+func (r ContactResponse) WithFields(fields []string) ContactResponse {
+	r.fields = fields
+	return r
+}
+
+// MarshalJSON drops the keys left out by WithFields
+func (r ContactResponse) MarshalJSON() ([]byte, error) {
+	type contactResponse ContactResponse
+	data, err := json.Marshal(contactResponse(r))
+	if err != nil || r.fields == nil {
+		return data, err
+	}
+
+	var rendered map[string]json.RawMessage
+	if err := json.Unmarshal(data, &rendered); err != nil {
+		return nil, err
+	}
+	for key := range rendered {
+		if !slices.Contains(r.fields, key) {
+			delete(rendered, key)
+		}
+	}
+	return json.Marshal(rendered)
 }
 
 type PaginatedResponse struct {
+	Data       []ContactResponse        `json:"data"`
+	Total      int64                    `json:"total"`
+	Page       int                      `json:"page"`
+	Limit      int                      `json:"limit"`
+	TotalPages int                      `json:"total_pages"`
+	Sort       string                   `json:"sort,omitempty"`
+	Facets     map[string][]FacetBucket `json:"facets,omitempty"`
+}
+
+// Highlight locates the parts of a contact field a search matched
+type Highlight struct {
+	Field   string       `json:"field"`
+	Text    string       `json:"text"`
+	Matches []MatchRange `json:"matches"`
+}
+
+// MatchRange is a matched part of a highlighted text, from Start up to but
+// not including End, counted in characters
+type MatchRange struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// ContactSuggestion is an autocomplete entry
+type ContactSuggestion struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// ContactNameWord is a word of a contact's folded name, which autocomplete
+// looks prefixes up in
+type ContactNameWord struct {
+	ContactID uint   `gorm:"primaryKey"`
+	Word      string `gorm:"primaryKey;size:100"`
+}
+
+// SearchIndexUpdate marks a contact changed since the search index last saw
+// it. Revision counts the changes, so that one made while the index was
+// being updated keeps the contact marked.
+type SearchIndexUpdate struct {
+	ContactID uint  `gorm:"primaryKey;autoIncrement:false"`
+	Revision  int64 `gorm:"not null;default:1"`
+}
+
+// ReindexResponse reports how many contacts a search index rebuild indexed
+type ReindexResponse struct {
+	Indexed int `json:"indexed"`
+}
+
+// AutocompleteResponse lists the suggestions for a prefix, best first
+type AutocompleteResponse struct {
+	Data []ContactSuggestion `json:"data"`
+}
+
+// FacetBucket counts the matching contacts sharing a value, such as a company
+type FacetBucket struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// CursorPaginatedResponse is a page of contacts read with a cursor, linking
+// to the adjacent pages instead of counting every match
+type CursorPaginatedResponse struct {
 	Data       []ContactResponse `json:"data"`
-	Total      int64             `json:"total"`
-	Page       int               `json:"page"`
 	Limit      int               `json:"limit"`
-	TotalPages int               `json:"total_pages"`
+	Sort       string            `json:"sort"`
+	NextCursor *string           `json:"next_cursor"`
+	PrevCursor *string           `json:"prev_cursor"`
 }
 
 func (c *Contact) ToResponse() ContactResponse {
+	tags := make([]string, 0, len(c.Tags))
+	for _, tag := range c.Tags {
+		tags = append(tags, tag.Name)
+	}
+
+	lists := make([]ContactListSummary, 0, len(c.Lists))
+	for _, list := range c.Lists {
+		lists = append(lists, ContactListSummary{ID: list.ID, Name: list.Name})
+	}
+
+	// Contacts stored before emails and phones had their own tables only
+	// carry the scalar columns, so render those as the primary entries
+	emails := make([]ContactEmailResponse, 0, len(c.Emails))
+	for _, email := range c.Emails {
+		emails = append(emails, ContactEmailResponse{Email: email.Email, Label: email.Label, Primary: email.Primary})
+	}
+	if len(emails) == 0 && c.Email != "" {
+		emails = append(emails, ContactEmailResponse{Email: c.Email, Label: LabelOther, Primary: true})
+	}
+
+	phones := make([]ContactPhoneResponse, 0, len(c.Phones))
+	for _, phone := range c.Phones {
+		phones = append(phones, ContactPhoneResponse{Number: phone.Number, Label: phone.Label, Primary: phone.Primary})
+	}
+	if len(phones) == 0 && c.Phone != "" {
+		phones = append(phones, ContactPhoneResponse{Number: c.Phone, Label: LabelOther, Primary: true})
+	}
+
+	addresses := c.Addresses
+	if addresses == nil {
+		addresses = []Address{}
+	}
+
+	dates := make([]SignificantDateResponse, 0, len(c.Dates))
+	for _, date := range c.Dates {
+		dates = append(dates, date.ToResponse())
+	}
+
+	var birthday *string
+	if date := c.Birthday(); date != nil {
+		value := date.String()
+		birthday = &value
+	}
+
+	customFields := map[string]any(c.CustomFields)
+	if customFields == nil {
+		customFields = map[string]any{}
+	}
+
+	// Relationships and highlights are only rendered when they were loaded
+	var relationships *[]RelationshipResponse
+	if c.Relationships != nil {
+		loaded := make([]RelationshipResponse, 0, len(c.Relationships))
+		for _, relationship := range c.Relationships {
+			loaded = append(loaded, relationship.ToResponse())
+		}
+		relationships = &loaded
+	}
+	var highlights *[]Highlight
+	if c.Highlights != nil {
+		highlights = &c.Highlights
+	}
+
 	return ContactResponse{
-		ID:        c.ID,
-		Name:      c.Name,
-		Email:     c.Email,
-		Phone:     c.Phone,
-		Company:   c.Company,
-		CreatedAt: c.CreatedAt,
-		UpdatedAt: c.UpdatedAt,
+		ID:            c.ID,
+		Name:          c.Name,
+		Email:         c.Email,
+		Phone:         c.Phone,
+		Company:       c.Company,
+		CompanyID:     c.CompanyID,
+		Birthday:      birthday,
+		Dates:         dates,
+		Emails:        emails,
+		Phones:        phones,
+		Addresses:     addresses,
+		Tags:          tags,
+		Lists:         lists,
+		CustomFields:  customFields,
+		NoteCount:     c.NoteCount,
+		PinnedNote:    c.PinnedNote,
+		Score:         c.Score,
+		Relationships: relationships,
+		Highlights:    highlights,
+		CreatedAt:     c.CreatedAt,
+		UpdatedAt:     c.UpdatedAt,
+	}
+}
+
+// BeforeSave keeps the accent-insensitive copies of name and company and the
+// phonetic key of the name used for searching and sorting in sync
+func (c *Contact) BeforeSave(tx *gorm.DB) error {
+	c.NameKey = FoldText(c.Name)
+	c.CompanyKey = FoldText(c.Company)
+	c.PhoneticKey = PhoneticKey(c.Name)
+	return nil
+}
+
+// Birthday returns the contact's birthday, or nil when it is not known
+func (c *Contact) Birthday() *PartialDate {
+	if c.BirthMonth == nil || c.BirthDay == nil {
+		return nil
+	}
+	return &PartialDate{Year: c.BirthYear, Month: *c.BirthMonth, Day: *c.BirthDay}
+}
+
+// SetBirthday parses a YYYY-MM-DD or --MM-DD birthday, an empty value clears it
+func (c *Contact) SetBirthday(value string) error {
+	if value == "" {
+		c.BirthYear, c.BirthMonth, c.BirthDay = nil, nil, nil
+		return nil
+	}
+
+	date, err := ParsePartialDate(value)
+	if err != nil {
+		return err
 	}
+	c.BirthYear, c.BirthMonth, c.BirthDay = date.Year, &date.Month, &date.Day
+	return nil
 }
@@ -0,0 +1,102 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Labels accepted for emails and phone numbers
+const (
+	LabelWork   = "work"
+	LabelHome   = "home"
+	LabelMobile = "mobile"
+	LabelOther  = "other"
+)
+
+type ContactEmail struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	ContactID uint      `json:"-" gorm:"index;not null"`
+	Email     string    `json:"email" gorm:"uniqueIndex;size:255;not null"`
+	Label     string    `json:"label" gorm:"size:20;not null;default:other"`
+	Primary   bool      `json:"primary" gorm:"column:is_primary;not null;default:false"`
+	CreatedAt time.Time `json:"-"`
+	UpdatedAt time.Time `json:"-"`
+}
+
+type ContactPhone struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	ContactID uint      `json:"-" gorm:"index;not null"`
+	Number    string    `json:"number" gorm:"size:20;not null"`
+	Digits    string    `json:"-" gorm:"size:20;index;not null;default:''"`
+	Label     string    `json:"label" gorm:"size:20;not null;default:other"`
+	Primary   bool      `json:"primary" gorm:"column:is_primary;not null;default:false"`
+	CreatedAt time.Time `json:"-"`
+	UpdatedAt time.Time `json:"-"`
+}
+
+type ContactEmailInput struct {
+	Email   string `json:"email" validate:"required,email"`
+	Label   string `json:"label" validate:"omitempty,oneof=work home mobile other"`
+	Primary bool   `json:"primary"`
+}
+
+type ContactPhoneInput struct {
+	Number  string `json:"number" validate:"required,min=10,max=20"`
+	Label   string `json:"label" validate:"omitempty,oneof=work home mobile other"`
+	Primary bool   `json:"primary"`
+}
+
+type ContactEmailResponse struct {
+	Email   string `json:"email"`
+	Label   string `json:"label"`
+	Primary bool   `json:"primary"`
+}
+
+type ContactPhoneResponse struct {
+	Number  string `json:"number"`
+	Label   string `json:"label"`
+	Primary bool   `json:"primary"`
+}
+
+// EmailsFromInput converts request entries into ContactEmail records
+func EmailsFromInput(inputs []ContactEmailInput) []ContactEmail {
+	emails := make([]ContactEmail, 0, len(inputs))
+	for _, input := range inputs {
+		emails = append(emails, ContactEmail{Email: input.Email, Label: input.Label, Primary: input.Primary})
+	}
+	return emails
+}
+
+// PhonesFromInput converts request entries into ContactPhone records
+func PhonesFromInput(inputs []ContactPhoneInput) []ContactPhone {
+	phones := make([]ContactPhone, 0, len(inputs))
+	for _, input := range inputs {
+		phones = append(phones, ContactPhone{Number: input.Number, Label: input.Label, Primary: input.Primary})
+	}
+	return phones
+}
+
+// BeforeSave keeps the digits of the number used for searching in sync
+func (p *ContactPhone) BeforeSave(tx *gorm.DB) error {
+	p.Digits = PhoneDigits(p.Number)
+	return nil
+}
+
+// PhoneDigits reduces a phone number to its digits without the Brazilian
+// country code, so "+55 (11) 99999-1111" and "11999991111" compare equal
+func PhoneDigits(number string) string {
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, number)
+
+	// Area code plus 8 or 9 digits, preceded by 55
+	if len(digits) >= 12 && strings.HasPrefix(digits, "55") {
+		digits = digits[2:]
+	}
+	return digits
+}
@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// Types accepted for custom field values
+const (
+	CustomFieldString = "string"
+	CustomFieldNumber = "number"
+	CustomFieldDate   = "date"
+	CustomFieldEnum   = "enum"
+	CustomFieldBool   = "bool"
+)
+
+// CustomFieldDateLayout is the format expected for date custom fields
+const CustomFieldDateLayout = "2006-01-02"
+
+type CustomFieldDefinition struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	Name       string     `json:"name" gorm:"uniqueIndex;size:50;not null"`
+	Label      string     `json:"label" gorm:"size:100"`
+	Type       string     `json:"type" gorm:"size:10;not null"`
+	Required   bool       `json:"required" gorm:"not null;default:false"`
+	EnumValues StringList `json:"enum_values"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+type CreateCustomFieldRequest struct {
+	Name       string   `json:"name" validate:"required,max=50,field_key"`
+	Label      string   `json:"label" validate:"omitempty,max=100"`
+	Type       string   `json:"type" validate:"required,oneof=string number date enum bool"`
+	Required   bool     `json:"required"`
+	EnumValues []string `json:"enum_values,omitempty" validate:"required_if=Type enum,omitempty,unique,dive,required,max=100"`
+}
+
+type UpdateCustomFieldRequest struct {
+	Label      *string   `json:"label,omitempty" validate:"omitempty,max=100"`
+	Required   *bool     `json:"required,omitempty"`
+	EnumValues *[]string `json:"enum_values,omitempty" validate:"omitempty,min=1,unique,dive,required,max=100"`
+}
@@ -0,0 +1,128 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Kinds of dates returned by the upcoming dates endpoint and the calendar feed
+const (
+	DateKindBirthday    = "birthday"
+	DateKindSignificant = "significant_date"
+)
+
+// PartialDate is a day of the year with an optional year, written as
+// YYYY-MM-DD or as --MM-DD, the vCard form for dates without a year
+type PartialDate struct {
+	Year  *int
+	Month int
+	Day   int
+}
+
+func ParsePartialDate(value string) (PartialDate, error) {
+	var date PartialDate
+	if len(value) == len("--01-02") && value[:2] == "--" {
+		// Parse against a leap year so that --02-29 is accepted
+		parsed, err := time.Parse("2006-01-02", "2000"+value[1:])
+		if err != nil {
+			return date, fmt.Errorf("invalid date %q", value)
+		}
+		date.Month, date.Day = int(parsed.Month()), parsed.Day()
+		return date, nil
+	}
+
+	parsed, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return date, fmt.Errorf("invalid date %q", value)
+	}
+	year := parsed.Year()
+	date.Year, date.Month, date.Day = &year, int(parsed.Month()), parsed.Day()
+	return date, nil
+}
+
+func (d PartialDate) String() string {
+	if d.Year == nil {
+		return fmt.Sprintf("--%02d-%02d", d.Month, d.Day)
+	}
+	return fmt.Sprintf("%04d-%02d-%02d", *d.Year, d.Month, d.Day)
+}
+
+// In returns the date in the given year. February 29 falls on February 28
+// in non-leap years.
+func (d PartialDate) In(year int, loc *time.Location) time.Time {
+	day := d.Day
+	if d.Month == 2 && day == 29 && !isLeapYear(year) {
+		day = 28
+	}
+	return time.Date(year, time.Month(d.Month), day, 0, 0, 0, 0, loc)
+}
+
+// NextOccurrence returns the first occurrence of the date on or after the
+// day of from, wrapping into the next year when it has already passed
+func (d PartialDate) NextOccurrence(from time.Time) time.Time {
+	today := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+	next := d.In(today.Year(), today.Location())
+	if next.Before(today) {
+		next = d.In(today.Year()+1, today.Location())
+	}
+	return next
+}
+
+func isLeapYear(year int) bool {
+	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
+}
+
+type SignificantDate struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	ContactID uint      `json:"-" gorm:"index;not null"`
+	Label     string    `json:"label" gorm:"size:50;not null"`
+	Year      *int      `json:"-"`
+	Month     int       `json:"-" gorm:"not null"`
+	Day       int       `json:"-" gorm:"not null"`
+	CreatedAt time.Time `json:"-"`
+	UpdatedAt time.Time `json:"-"`
+}
+
+func (d *SignificantDate) Date() PartialDate {
+	return PartialDate{Year: d.Year, Month: d.Month, Day: d.Day}
+}
+
+func (d *SignificantDate) SetDate(date PartialDate) {
+	d.Year, d.Month, d.Day = date.Year, date.Month, date.Day
+}
+
+func (d *SignificantDate) ToResponse() SignificantDateResponse {
+	return SignificantDateResponse{ID: d.ID, Label: d.Label, Date: d.Date().String()}
+}
+
+type SignificantDateRequest struct {
+	Label string `json:"label" validate:"required,max=50"`
+	Date  string `json:"date" validate:"required,partial_date"`
+}
+
+type SignificantDateResponse struct {
+	ID    uint   `json:"id"`
+	Label string `json:"label"`
+	Date  string `json:"date"`
+}
+
+// ContactDate is a birthday or significant date together with its contact
+type ContactDate struct {
+	ID          uint
+	ContactID   uint
+	ContactName string
+	Kind        string
+	Label       string
+	Date        PartialDate
+}
+
+type UpcomingDate struct {
+	ContactID   uint   `json:"contact_id"`
+	ContactName string `json:"contact_name"`
+	Kind        string `json:"kind"`
+	Label       string `json:"label"`
+	Date        string `json:"date"`
+	Original    string `json:"original"`
+	DaysUntil   int    `json:"days_until"`
+	Years       *int   `json:"years,omitempty"`
+}
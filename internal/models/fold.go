@@ -0,0 +1,37 @@
+package models
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// FoldText lowercases text and strips its diacritics, so that "João" and
+// "joao" compare equal. Migration 000013 back-fills the folded columns with
+// the equivalent lower(f_unaccent(...)) in PostgreSQL.
+func FoldText(text string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, err := transform.String(t, text)
+	if err != nil {
+		folded = text
+	}
+	return strings.ToLower(folded)
+}
+
+// NameWords splits a name into its distinct folded words, runs of letters and
+// digits
+func NameWords(name string) []string {
+	var words []string
+	for _, word := range strings.FieldsFunc(FoldText(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if !slices.Contains(words, word) {
+			words = append(words, word)
+		}
+	}
+	return words
+}
@@ -0,0 +1,32 @@
+package models
+
+// Outcomes of an imported row
+const (
+	ImportCreated = "created"
+	ImportUpdated = "updated"
+	ImportSkipped = "skipped"
+	ImportFailed  = "failed"
+)
+
+// ImportRowResult reports what happened to one row of an imported file,
+// or what would happen on a dry run. Failed rows carry the error and
+// details creating or updating the contact through the API would give.
+type ImportRowResult struct {
+	Line      int    `json:"line"`
+	Status    string `json:"status"`
+	ContactID *uint  `json:"contact_id,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+	Error     string `json:"error,omitempty"`
+	Details   string `json:"details,omitempty"`
+}
+
+// ImportReport sums up an import, row by row
+type ImportReport struct {
+	DryRun         bool              `json:"dry_run"`
+	Created        int               `json:"created"`
+	Updated        int               `json:"updated"`
+	Skipped        int               `json:"skipped"`
+	Failed         int               `json:"failed"`
+	IgnoredColumns []string          `json:"ignored_columns"`
+	Rows           []ImportRowResult `json:"rows"`
+}
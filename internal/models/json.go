@@ -0,0 +1,84 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// JSONMap is a JSON object stored in a JSONB column (JSON on SQLite)
+type JSONMap map[string]any
+
+func (m JSONMap) Value() (driver.Value, error) {
+	if m == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func (m *JSONMap) Scan(value any) error {
+	data, err := jsonBytes(value)
+	if err != nil || data == nil {
+		*m = JSONMap{}
+		return err
+	}
+	return json.Unmarshal(data, m)
+}
+
+func (JSONMap) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	return jsonDataType(db)
+}
+
+// StringList is a list of strings stored as a JSON array
+type StringList []string
+
+func (l StringList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal([]string(l))
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func (l *StringList) Scan(value any) error {
+	data, err := jsonBytes(value)
+	if err != nil || data == nil {
+		*l = StringList{}
+		return err
+	}
+	return json.Unmarshal(data, l)
+}
+
+func (StringList) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	return jsonDataType(db)
+}
+
+func jsonDataType(db *gorm.DB) string {
+	if db.Dialector.Name() == "postgres" {
+		return "JSONB"
+	}
+	return "JSON"
+}
+
+func jsonBytes(value any) ([]byte, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		return v, nil
+	case string:
+		return []byte(v), nil
+	default:
+		return nil, fmt.Errorf("unsupported JSON column value %T", value)
+	}
+}
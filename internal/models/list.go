@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+type ContactList struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	Name        string    `json:"name" gorm:"size:100;not null"`
+	Description string    `json:"description" gorm:"type:text"`
+	MemberCount int64     `json:"member_count" gorm:"->;-:migration"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	Members     []Contact `json:"-" gorm:"many2many:contact_list_members;"`
+}
+
+type CreateContactListRequest struct {
+	Name        string `json:"name" validate:"required,min=1,max=100"`
+	Description string `json:"description" validate:"omitempty,max=1000"`
+}
+
+type UpdateContactListRequest struct {
+	Name        *string `json:"name,omitempty" validate:"omitempty,min=1,max=100"`
+	Description *string `json:"description,omitempty" validate:"omitempty,max=1000"`
+}
+
+type ListMembersRequest struct {
+	ContactIDs []uint `json:"contact_ids" validate:"required,min=1,max=500,dive,required"`
+}
+
+type ContactListSummary struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+type PaginatedContactListResponse struct {
+	Data       []ContactList `json:"data"`
+	Total      int64         `json:"total"`
+	Page       int           `json:"page"`
+	Limit      int           `json:"limit"`
+	TotalPages int           `json:"total_pages"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+type Note struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	ContactID uint       `json:"contact_id" gorm:"index;not null"`
+	Body      string     `json:"body" gorm:"type:text;not null"`
+	Author    string     `json:"author" gorm:"size:100;not null"`
+	Pinned    bool       `json:"pinned" gorm:"not null;default:false"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	EditedAt  *time.Time `json:"edited_at"`
+}
+
+type CreateNoteRequest struct {
+	Body   string `json:"body" validate:"required,max=10000"`
+	Author string `json:"author" validate:"required,max=100"`
+	Pinned bool   `json:"pinned"`
+}
+
+type UpdateNoteRequest struct {
+	Body   *string `json:"body,omitempty" validate:"omitempty,min=1,max=10000"`
+	Pinned *bool   `json:"pinned,omitempty"`
+}
+
+type PaginatedNoteResponse struct {
+	Data       []Note `json:"data"`
+	Total      int64  `json:"total"`
+	Page       int    `json:"page"`
+	Limit      int    `json:"limit"`
+	TotalPages int    `json:"total_pages"`
+}
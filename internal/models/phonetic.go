@@ -0,0 +1,74 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// phoneticRules rewrite Portuguese spellings into the letter that sounds the
+// same, in order, after accents are folded. They follow the BuscaBR idea:
+// unify consonants that sound alike, then drop vowels and silent letters.
+var phoneticRules = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`ph`), "f"},
+	{regexp.MustCompile(`lh`), "l"},
+	{regexp.MustCompile(`nh`), "n"},
+	{regexp.MustCompile(`[cs]h`), "s"},
+	{regexp.MustCompile(`sc([ei])`), "s$1"},
+	{regexp.MustCompile(`qu([ei])`), "k$1"},
+	{regexp.MustCompile(`gu([ei])`), "g$1"},
+	{regexp.MustCompile(`c([ei])`), "s$1"},
+	{regexp.MustCompile(`g([ei])`), "j$1"},
+	{regexp.MustCompile(`[cq]`), "k"},
+	{regexp.MustCompile(`[zx]`), "s"},
+	{regexp.MustCompile(`y`), "i"},
+	{regexp.MustCompile(`w`), "v"},
+	{regexp.MustCompile(`n`), "m"},
+	{regexp.MustCompile(`[aeiouh]`), ""},
+}
+
+// cedilla turns ç into the s it sounds like, before folding accents would
+// turn it into a c. Text is composed first, so a c followed by a combining
+// cedilla is caught too.
+var cedilla = strings.NewReplacer("ç", "s", "Ç", "s")
+
+// PhoneticKey reduces each word of a name to a Portuguese sound-alike code,
+// so that "Filipe Sousa" and "Felipe Souza" share the key "flp s"
+func PhoneticKey(text string) string {
+	words := strings.FieldsFunc(FoldText(cedilla.Replace(norm.NFC.String(text))), func(r rune) bool {
+		return r < 'a' || r > 'z'
+	})
+
+	codes := make([]string, 0, len(words))
+	for _, word := range words {
+		if code := phoneticCode(word); code != "" {
+			codes = append(codes, code)
+		}
+	}
+	return strings.Join(codes, " ")
+}
+
+func phoneticCode(word string) string {
+	code := word
+	for _, rule := range phoneticRules {
+		code = rule.pattern.ReplaceAllString(code, rule.replacement)
+	}
+
+	// Words made only of vowels keep their first letter
+	if code == "" {
+		return word[:1]
+	}
+
+	// Doubled letters sound like a single one
+	var b strings.Builder
+	for i := 0; i < len(code); i++ {
+		if i == 0 || code[i] != code[i-1] {
+			b.WriteByte(code[i])
+		}
+	}
+	return b.String()
+}
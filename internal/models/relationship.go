@@ -0,0 +1,73 @@
+package models
+
+import "time"
+
+// Relationship types, read as "the related contact is the <type> of this one"
+const (
+	RelationshipManager     = "manager"
+	RelationshipReport      = "report"
+	RelationshipAssistant   = "assistant"
+	RelationshipAssistantOf = "assistant_of"
+	RelationshipSpouse      = "spouse"
+	RelationshipReferredBy  = "referred_by"
+	RelationshipReferred    = "referred"
+	RelationshipCustom      = "custom"
+)
+
+// RelationshipInverses maps each type to the one seen from the related contact
+var RelationshipInverses = map[string]string{
+	RelationshipManager:     RelationshipReport,
+	RelationshipReport:      RelationshipManager,
+	RelationshipAssistant:   RelationshipAssistantOf,
+	RelationshipAssistantOf: RelationshipAssistant,
+	RelationshipSpouse:      RelationshipSpouse,
+	RelationshipReferredBy:  RelationshipReferred,
+	RelationshipReferred:    RelationshipReferredBy,
+	RelationshipCustom:      RelationshipCustom,
+}
+
+type Relationship struct {
+	ID               uint      `json:"id" gorm:"primaryKey"`
+	ContactID        uint      `json:"contact_id" gorm:"index;not null"`
+	RelatedContactID uint      `json:"related_contact_id" gorm:"index;not null"`
+	Type             string    `json:"type" gorm:"size:20;not null"`
+	Label            string    `json:"label" gorm:"size:50;not null;default:''"`
+	CreatedAt        time.Time `json:"created_at"`
+	RelatedContact   *Contact  `json:"-" gorm:"foreignKey:RelatedContactID"`
+}
+
+type CreateRelationshipRequest struct {
+	RelatedContactID uint   `json:"related_contact_id" validate:"required"`
+	Type             string `json:"type" validate:"required,oneof=manager report assistant assistant_of spouse referred_by referred custom"`
+	Label            string `json:"label" validate:"required_if=Type custom,omitempty,max=50"`
+	Bidirectional    bool   `json:"bidirectional"`
+}
+
+type RelatedContact struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type RelationshipResponse struct {
+	ID             uint           `json:"id"`
+	Type           string         `json:"type"`
+	Label          string         `json:"label,omitempty"`
+	RelatedContact RelatedContact `json:"related_contact"`
+	CreatedAt      time.Time      `json:"created_at"`
+}
+
+func (r *Relationship) ToResponse() RelationshipResponse {
+	response := RelationshipResponse{
+		ID:             r.ID,
+		Type:           r.Type,
+		Label:          r.Label,
+		RelatedContact: RelatedContact{ID: r.RelatedContactID},
+		CreatedAt:      r.CreatedAt,
+	}
+	if r.RelatedContact != nil {
+		response.RelatedContact.Name = r.RelatedContact.Name
+		response.RelatedContact.Email = r.RelatedContact.Email
+	}
+	return response
+}
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+type SmartList struct {
+	ID          uint                `json:"id" gorm:"primaryKey"`
+	Name        string              `json:"name" gorm:"uniqueIndex;size:100;not null"`
+	Description string              `json:"description" gorm:"type:text"`
+	Definition  SmartListDefinition `json:"definition" gorm:"not null"`
+	MemberCount *int64              `json:"member_count,omitempty" gorm:"-"`
+	CreatedAt   time.Time           `json:"created_at"`
+	UpdatedAt   time.Time           `json:"updated_at"`
+	// DefinitionError explains why a definition broken by a later schema
+	// change cannot be evaluated
+	DefinitionError string `json:"definition_error,omitempty" gorm:"-"`
+}
+
+// SmartListDefinition is the saved search evaluated each time a smart list is read
+type SmartListDefinition struct {
+	Query        string               `json:"query,omitempty" validate:"omitempty,max=100"`
+	Tags         []string             `json:"tags,omitempty" validate:"omitempty,dive,required,max=50"`
+	TagMode      string               `json:"tag_mode,omitempty" validate:"omitempty,oneof=any all"`
+	CompanyID    *uint                `json:"company_id,omitempty"`
+	CustomFields map[string]string    `json:"custom_fields,omitempty"`
+	Conditions   []SmartListCondition `json:"conditions,omitempty" validate:"omitempty,max=20,dive"`
+}
+
+type SmartListCondition struct {
+	Field    string `json:"field" validate:"required,oneof=name email phone company created_at updated_at"`
+	Operator string `json:"operator" validate:"required,oneof=equals contains starts_with before after within_last_days"`
+	Value    string `json:"value" validate:"required,max=100"`
+}
+
+func (d SmartListDefinition) Value() (driver.Value, error) {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func (d *SmartListDefinition) Scan(value any) error {
+	data, err := jsonBytes(value)
+	if err != nil || data == nil {
+		*d = SmartListDefinition{}
+		return err
+	}
+	return json.Unmarshal(data, d)
+}
+
+func (SmartListDefinition) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	return jsonDataType(db)
+}
+
+type CreateSmartListRequest struct {
+	Name        string              `json:"name" validate:"required,min=1,max=100"`
+	Description string              `json:"description" validate:"omitempty,max=1000"`
+	Definition  SmartListDefinition `json:"definition"`
+}
+
+type UpdateSmartListRequest struct {
+	Name        *string              `json:"name,omitempty" validate:"omitempty,min=1,max=100"`
+	Description *string              `json:"description,omitempty" validate:"omitempty,max=1000"`
+	Definition  *SmartListDefinition `json:"definition,omitempty"`
+}
+
+type PaginatedSmartListResponse struct {
+	Data       []SmartList `json:"data"`
+	Total      int64       `json:"total"`
+	Page       int         `json:"page"`
+	Limit      int         `json:"limit"`
+	TotalPages int         `json:"total_pages"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+type Tag struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Name      string    `json:"name" gorm:"uniqueIndex;size:50;not null"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"-"`
+}
+
+type TagsRequest struct {
+	Tags []string `json:"tags" validate:"required,min=1,dive,required,max=50"`
+}
+
+type TagResponse struct {
+	ID           uint   `json:"id"`
+	Name         string `json:"name"`
+	ContactCount int64  `json:"contact_count"`
+}
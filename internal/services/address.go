@@ -0,0 +1,91 @@
+package services
+
+import (
+	"errors"
+
+	"api-contacts-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+var ErrAddressNotFound = errors.New("address not found")
+
+type AddressService struct {
+	db *gorm.DB
+}
+
+func NewAddressService(db *gorm.DB) *AddressService {
+	return &AddressService{db: db}
+}
+
+func (s *AddressService) GetAddresses(contactID uint) ([]models.Address, error) {
+	var contact models.Contact
+	if err := s.db.First(&contact, contactID).Error; err != nil {
+		return nil, err
+	}
+
+	addresses := []models.Address{}
+	if err := s.db.Where("contact_id = ?", contactID).Order("id").Find(&addresses).Error; err != nil {
+		return nil, err
+	}
+
+	return addresses, nil
+}
+
+func (s *AddressService) CreateAddress(contactID uint, req *models.AddressRequest) (*models.Address, error) {
+	var contact models.Contact
+	if err := s.db.First(&contact, contactID).Error; err != nil {
+		return nil, err
+	}
+
+	address := models.Address{ContactID: contactID}
+	req.Apply(&address)
+
+	if err := s.db.Create(&address).Error; err != nil {
+		return nil, err
+	}
+
+	return &address, nil
+}
+
+func (s *AddressService) UpdateAddress(contactID, addressID uint, req *models.AddressRequest) (*models.Address, error) {
+	address, err := s.findAddress(contactID, addressID)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Apply(address)
+
+	if err := s.db.Save(address).Error; err != nil {
+		return nil, err
+	}
+
+	return address, nil
+}
+
+func (s *AddressService) DeleteAddress(contactID, addressID uint) error {
+	address, err := s.findAddress(contactID, addressID)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Delete(address).Error
+}
+
+// findAddress loads an address making sure it belongs to the given contact
+func (s *AddressService) findAddress(contactID, addressID uint) (*models.Address, error) {
+	var contact models.Contact
+	if err := s.db.First(&contact, contactID).Error; err != nil {
+		return nil, err
+	}
+
+	var address models.Address
+	if err := s.db.Where("contact_id = ?", contactID).First(&address, addressID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrAddressNotFound
+		}
+		return nil, err
+	}
+
+	return &address, nil
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"strings"
+
+	"api-contacts-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// autocompleteCandidates caps the contacts each prefix lookup of Autocomplete
+// reads before ranking, so that short prefixes matching much of the table
+// cost the same as long ones
+const autocompleteCandidates = 200
+
+// autocompleteBoosted is how many of the most picked and recently updated
+// contacts Autocomplete also checks against the prefix, so that boosted
+// contacts are suggested even past the alphabetical candidates
+const autocompleteBoosted = 1000
+
+// Autocomplete returns up to limit contacts whose name, any word of it, or
+// email starts with the prefix. Whole-name matches come first, then the
+// contacts picked most often from suggestions, then the recently updated.
+// Each kind of match is a separate lookup reading a bounded range of its
+// prefix index, and one more reads the most boosted contacts in boost
+// order; only the candidates found are ranked. A prefix matching more than
+// autocompleteCandidates contacts may thus miss an unboosted contact late in
+// alphabetical order, never one among the autocompleteBoosted most boosted.
+// Only the columns of a suggestion are read.
+func (s *ContactService) Autocomplete(prefix string, limit int) ([]models.Contact, error) {
+	folded := likeEscaper.Replace(strings.TrimSpace(models.FoldText(prefix)))
+	if folded == "" {
+		return []models.Contact{}, nil
+	}
+	start := folded + "%"
+
+	names := s.db.Model(&models.Contact{}).
+		Select("contacts.id AS contact_id, 0 AS tier").
+		Where(`contacts.name_key LIKE ? ESCAPE '\'`, start).
+		Order("contacts.name_key").
+		Limit(autocompleteCandidates)
+	words := s.db.Model(&models.ContactNameWord{}).
+		Select("contact_id, 1 AS tier").
+		Where(`word LIKE ? ESCAPE '\'`, start).
+		Order("word").
+		Limit(autocompleteCandidates)
+	emails := s.db.Model(&models.Contact{}).
+		Select("contacts.id AS contact_id, 1 AS tier").
+		Where(`LOWER(contacts.email) LIKE ? ESCAPE '\'`, start).
+		Order("LOWER(contacts.email)").
+		Limit(autocompleteCandidates)
+	mostBoosted := s.db.Model(&models.Contact{}).
+		Select("contacts.id, contacts.name_key, contacts.email").
+		Order("contacts.selection_count DESC, contacts.updated_at DESC, contacts.id").
+		Limit(autocompleteBoosted)
+	boosted := s.db.Table("(?) AS boosted", mostBoosted).
+		Select(`boosted.id AS contact_id, CASE WHEN boosted.name_key LIKE ? ESCAPE '\' THEN 0 ELSE 1 END AS tier`, start).
+		Where(`boosted.name_key LIKE ? ESCAPE '\' OR LOWER(boosted.email) LIKE ? ESCAPE '\' OR EXISTS (SELECT 1 FROM contact_name_words `+
+			`WHERE contact_name_words.contact_id = boosted.id AND contact_name_words.word LIKE ? ESCAPE '\')`, start, start, start)
+	candidates := s.db.Raw("SELECT * FROM (?) AS names UNION ALL SELECT * FROM (?) AS words UNION ALL SELECT * FROM (?) AS emails "+
+		"UNION ALL SELECT * FROM (?) AS boosted", names, words, emails, boosted)
+
+	contacts := []models.Contact{}
+	err := s.db.Model(&models.Contact{}).
+		Select("contacts.id, contacts.name, contacts.email, MIN(candidates.tier) AS tier").
+		Joins("JOIN (?) AS candidates ON candidates.contact_id = contacts.id", candidates).
+		Group("contacts.id, contacts.name, contacts.email, contacts.selection_count, contacts.updated_at").
+		Order("tier, contacts.selection_count DESC, contacts.updated_at DESC, contacts.id").
+		Limit(limit).
+		Find(&contacts).Error
+	return contacts, err
+}
+
+// replaceNameWords stores the words of a contact's name autocomplete looks up,
+// none for a deleted contact
+func replaceNameWords(tx *gorm.DB, contactID uint, name string) error {
+	if err := tx.Where("contact_id = ?", contactID).Delete(&models.ContactNameWord{}).Error; err != nil {
+		return err
+	}
+
+	words := []models.ContactNameWord{}
+	for _, word := range models.NameWords(name) {
+		words = append(words, models.ContactNameWord{ContactID: contactID, Word: word})
+	}
+	if len(words) == 0 {
+		return nil
+	}
+	return tx.Create(&words).Error
+}
+
+// RecordSelection counts that a contact was picked from the suggestions,
+// which boosts it in later ones. The contact's updated_at is left alone.
+func (s *ContactService) RecordSelection(id uint) error {
+	result := s.db.Model(&models.Contact{}).
+		Where("id = ?", id).
+		UpdateColumn("selection_count", gorm.Expr("selection_count + 1"))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"api-contacts-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCompanyNotFound = errors.New("company not found")
+	ErrCompanyExists   = errors.New("company already exists")
+)
+
+type CompanyService struct {
+	db    *gorm.DB
+	index SearchIndex
+}
+
+// NewCompanyService creates a service keeping index in step with company
+// renames, or no index when nil
+func NewCompanyService(db *gorm.DB, index SearchIndex) *CompanyService {
+	if index == nil {
+		index = SQLIndex{}
+	}
+	return &CompanyService{db: db, index: index}
+}
+
+func (s *CompanyService) GetCompanies(page, limit int) ([]models.Company, int64, error) {
+	companies := []models.Company{}
+	var total int64
+
+	if err := s.db.Model(&models.Company{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := s.db.Scopes(withContactCount).Offset(offset).Limit(limit).Order("companies.sort_name, companies.id").Find(&companies).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return companies, total, nil
+}
+
+func (s *CompanyService) GetCompany(id uint) (*models.Company, error) {
+	var company models.Company
+	if err := s.db.Scopes(withContactCount).First(&company, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrCompanyNotFound
+		}
+		return nil, err
+	}
+	return &company, nil
+}
+
+func (s *CompanyService) CreateCompany(req *models.CreateCompanyRequest) (*models.Company, error) {
+	company := models.Company{
+		Name:           strings.TrimSpace(req.Name),
+		NormalizedName: models.NormalizeCompanyName(req.Name),
+		Website:        req.Website,
+	}
+
+	if err := s.checkNameAvailable(0, company.NormalizedName); err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Create(&company).Error; err != nil {
+		return nil, err
+	}
+
+	return &company, nil
+}
+
+func (s *CompanyService) UpdateCompany(id uint, req *models.UpdateCompanyRequest) (*models.Company, error) {
+	company, err := s.GetCompany(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Website != nil {
+		company.Website = *req.Website
+	}
+
+	renamed := req.Name != nil && strings.TrimSpace(*req.Name) != company.Name
+	if renamed {
+		company.Name = strings.TrimSpace(*req.Name)
+		company.NormalizedName = models.NormalizeCompanyName(company.Name)
+		if err := s.checkNameAvailable(company.ID, company.NormalizedName); err != nil {
+			return nil, err
+		}
+	}
+
+	// Renaming changes the company searched on each linked contact
+	var renamedIDs []uint
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(company).Error; err != nil {
+			return err
+		}
+
+		// Keep the legacy company column of linked contacts in sync
+		if !renamed {
+			return nil
+		}
+		err := tx.Unscoped().Model(&models.Contact{}).
+			Where("company_id = ?", company.ID).
+			UpdateColumns(map[string]any{"company": company.Name, "company_key": models.FoldText(company.Name)}).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Contact{}).Where("company_id = ?", company.ID).Pluck("id", &renamedIDs).Error; err != nil {
+			return err
+		}
+		return queueIndexUpdates(tx, s.index, renamedIDs...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	refreshSearchIndex(s.db, s.index, renamedIDs...)
+
+	return company, nil
+}
+
+func (s *CompanyService) DeleteCompany(id uint) error {
+	company, err := s.GetCompany(id)
+	if err != nil {
+		return err
+	}
+
+	// Contacts keep the company name as text but lose the link
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&models.Contact{}).
+			Where("company_id = ?", company.ID).
+			UpdateColumn("company_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Company{}, company.ID).Error
+	})
+}
+
+func (s *CompanyService) checkNameAvailable(id uint, normalizedName string) error {
+	var count int64
+	if err := s.db.Model(&models.Company{}).
+		Where("normalized_name = ? AND id <> ?", normalizedName, id).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrCompanyExists
+	}
+	return nil
+}
+
+// resolveCompany links a contact to a company, either by ID or by name. A
+// name that matches no existing company creates it.
+func resolveCompany(tx *gorm.DB, contact *models.Contact, companyID *uint) error {
+	if companyID != nil {
+		var company models.Company
+		if err := tx.First(&company, *companyID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return ErrCompanyNotFound
+			}
+			return err
+		}
+		contact.CompanyID = &company.ID
+		contact.Company = company.Name
+		return nil
+	}
+
+	normalizedName := models.NormalizeCompanyName(contact.Company)
+	if normalizedName == "" {
+		contact.CompanyID = nil
+		contact.Company = ""
+		return nil
+	}
+
+	company := models.Company{Name: strings.TrimSpace(contact.Company), NormalizedName: normalizedName}
+	if err := tx.Where("normalized_name = ?", normalizedName).FirstOrCreate(&company).Error; err != nil {
+		return err
+	}
+	contact.CompanyID = &company.ID
+	contact.Company = company.Name
+	return nil
+}
+
+// withContactCount selects companies along with their number of live contacts
+func withContactCount(db *gorm.DB) *gorm.DB {
+	return db.Select("companies.*, (SELECT COUNT(*) FROM contacts WHERE contacts.company_id = companies.id AND contacts.deleted_at IS NULL) AS contact_count")
+}
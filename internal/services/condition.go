@@ -0,0 +1,100 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"api-contacts-go/internal/models"
+)
+
+// Operators accepted by ContactCondition
+const (
+	OpEquals         = "equals"
+	OpContains       = "contains"
+	OpStartsWith     = "starts_with"
+	OpBefore         = "before"
+	OpAfter          = "after"
+	OpWithinLastDays = "within_last_days"
+)
+
+// ConditionDateLayout is the format of dates compared with before and after
+const ConditionDateLayout = "2006-01-02"
+
+// ContactCondition compares a contact column with a value, such as company
+// contains "Corp" or created_at within_last_days 30
+type ContactCondition struct {
+	Field    string
+	Operator string
+	Value    string
+}
+
+var (
+	// Text columns compared in lowercase, name and company also without accents
+	conditionTextFields = map[string]string{
+		"name":    "contacts.name_key",
+		"email":   "LOWER(contacts.email)",
+		"phone":   "LOWER(contacts.phone)",
+		"company": "contacts.company_key",
+	}
+	conditionDateFields = map[string]string{
+		"created_at": "contacts.created_at",
+		"updated_at": "contacts.updated_at",
+	}
+)
+
+// checkCondition describes why a condition cannot be evaluated, or returns ""
+func checkCondition(condition ContactCondition) string {
+	if _, ok := conditionTextFields[condition.Field]; ok {
+		switch condition.Operator {
+		case OpEquals, OpContains, OpStartsWith:
+			return ""
+		}
+		return fmt.Sprintf("operator '%s' does not apply to text field '%s'", condition.Operator, condition.Field)
+	}
+
+	if _, ok := conditionDateFields[condition.Field]; ok {
+		switch condition.Operator {
+		case OpBefore, OpAfter:
+			if _, err := time.Parse(ConditionDateLayout, condition.Value); err != nil {
+				return fmt.Sprintf("'%s' must be a date in YYYY-MM-DD format", condition.Field)
+			}
+			return ""
+		case OpWithinLastDays:
+			if days, err := strconv.Atoi(condition.Value); err != nil || days < 1 {
+				return fmt.Sprintf("'%s' within_last_days needs a positive number of days", condition.Field)
+			}
+			return ""
+		}
+		return fmt.Sprintf("operator '%s' does not apply to date field '%s'", condition.Operator, condition.Field)
+	}
+
+	return fmt.Sprintf("unknown field '%s'", condition.Field)
+}
+
+// conditionClause translates a checked condition into a WHERE clause.
+// Relative dates are resolved at call time so saved conditions stay live.
+func conditionClause(condition ContactCondition) (string, any) {
+	if column, ok := conditionTextFields[condition.Field]; ok {
+		value := models.FoldText(condition.Value)
+		switch condition.Operator {
+		case OpContains:
+			return column + ` LIKE ? ESCAPE '\'`, "%" + likeEscaper.Replace(value) + "%"
+		case OpStartsWith:
+			return column + ` LIKE ? ESCAPE '\'`, likeEscaper.Replace(value) + "%"
+		}
+		return column + " = ?", value
+	}
+
+	column := conditionDateFields[condition.Field]
+	switch condition.Operator {
+	case OpWithinLastDays:
+		days, _ := strconv.Atoi(condition.Value)
+		return column + " >= ?", time.Now().AddDate(0, 0, -days)
+	case OpAfter:
+		date, _ := time.Parse(ConditionDateLayout, condition.Value)
+		return column + " >= ?", date.AddDate(0, 0, 1)
+	}
+	date, _ := time.Parse(ConditionDateLayout, condition.Value)
+	return column + " < ?", date
+}
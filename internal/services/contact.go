@@ -1,33 +1,79 @@
 package services
 
 import (
+	"strconv"
 	"strings"
+	"time"
 
+	"api-contacts-go/internal/filter"
 	"api-contacts-go/internal/models"
 
 	"gorm.io/gorm"
 )
 
+// Tag match modes accepted by ContactFilter.TagMode
+const (
+	TagMatchAny = "any"
+	TagMatchAll = "all"
+)
+
+// ContactFilter narrows the result set of GetContacts and SearchContacts,
+// and Sort orders it, falling back to DefaultContactSort or DefaultSearchSort.
+// Fields, when set, limits the columns and associations loaded.
+// Time bounds are half-open: after is inclusive and before exclusive.
+type ContactFilter struct {
+	Tags          []string
+	TagMode       string
+	CustomFields  []CustomFieldFilter
+	CompanyID     *uint
+	ListID        *uint
+	Conditions    []ContactCondition
+	IDs           []uint
+	Company       string
+	EmailDomain   string
+	HasPhone      *bool
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	UpdatedAfter  *time.Time
+	UpdatedBefore *time.Time
+	Expression    filter.Expr
+	Sort          []SortField
+	Fields        *FieldSet
+}
+
 type ContactService struct {
-	db *gorm.DB
+	db    *gorm.DB
+	index SearchIndex
 }
 
-func NewContactService(db *gorm.DB) *ContactService {
-	return &ContactService{db: db}
+// NewContactService creates a service searching with index, or with the
+// database alone when index is nil
+func NewContactService(db *gorm.DB, index SearchIndex) *ContactService {
+	if index == nil {
+		index = SQLIndex{}
+	}
+	return &ContactService{db: db, index: index}
 }
 
-func (s *ContactService) GetContacts(page, limit int) ([]models.Contact, int64, error) {
+func (s *ContactService) GetContacts(page, limit int, filter ContactFilter) ([]models.Contact, int64, error) {
 	var contacts []models.Contact
 	var total int64
 
+	query := s.applyFilter(s.db.Model(&models.Contact{}), filter).Session(&gorm.Session{})
+
 	// Count total records
-	if err := s.db.Model(&models.Contact{}).Count(&total).Error; err != nil {
+	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
 	// Get paginated results
 	offset := (page - 1) * limit
-	if err := s.db.Offset(offset).Limit(limit).Order("created_at DESC").Find(&contacts).Error; err != nil {
+	sort := filter.sortOr(DefaultContactSort)
+	paged := filter.Fields.selectColumns(applySort(query, sort, false), sort)
+	if err := paged.Scopes(filter.Fields.preload).Offset(offset).Limit(limit).Find(&contacts).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := filter.Fields.load(s.db, contacts); err != nil {
 		return nil, 0, err
 	}
 
@@ -36,19 +82,48 @@ func (s *ContactService) GetContacts(page, limit int) ([]models.Contact, int64,
 
 func (s *ContactService) GetContact(id uint) (*models.Contact, error) {
 	var contact models.Contact
-	if err := s.db.First(&contact, id).Error; err != nil {
+	if err := s.db.Scopes(preloadAssociations).First(&contact, id).Error; err != nil {
+		return nil, err
+	}
+
+	contacts := []models.Contact{contact}
+	if err := loadNoteSummaries(s.db, contacts); err != nil {
 		return nil, err
 	}
-	return &contact, nil
+	return &contacts[0], nil
 }
 
 func (s *ContactService) CreateContact(contact *models.Contact) error {
-	return s.db.Create(contact).Error
+	if err := prepareContactMethods(contact); err != nil {
+		return err
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := checkEmailsAvailable(tx, 0, contact.Emails); err != nil {
+			return err
+		}
+		if err := resolveCompany(tx, contact, contact.CompanyID); err != nil {
+			return err
+		}
+		if err := tx.Create(contact).Error; err != nil {
+			return err
+		}
+		if err := replaceNameWords(tx, contact.ID, contact.Name); err != nil {
+			return err
+		}
+		return queueIndexUpdates(tx, s.index, contact.ID)
+	})
+	if err != nil {
+		return err
+	}
+
+	refreshSearchIndex(s.db, s.index, contact.ID)
+	return nil
 }
 
 func (s *ContactService) UpdateContact(id uint, req *models.UpdateContactRequest) (*models.Contact, error) {
 	var contact models.Contact
-	if err := s.db.First(&contact, id).Error; err != nil {
+	if err := s.db.Preload("Emails").Preload("Phones").First(&contact, id).Error; err != nil {
 		return nil, err
 	}
 
@@ -56,49 +131,325 @@ func (s *ContactService) UpdateContact(id uint, req *models.UpdateContactRequest
 	if req.Name != nil {
 		contact.Name = *req.Name
 	}
+	if req.Company != nil {
+		contact.Company = *req.Company
+	}
+	if req.CustomFields != nil {
+		contact.CustomFields = mergeCustomFields(contact.CustomFields, req.CustomFields)
+	}
+	if req.Birthday != nil {
+		if err := contact.SetBirthday(*req.Birthday); err != nil {
+			return nil, err
+		}
+	}
+
+	// A new list replaces the stored one, while a bare email or phone
+	// only swaps out the current primary entry
+	if req.Emails != nil {
+		contact.Emails = models.EmailsFromInput(*req.Emails)
+		contact.Email = ""
+	}
 	if req.Email != nil {
+		for i := range contact.Emails {
+			if contact.Emails[i].Primary && req.Emails == nil {
+				contact.Emails[i].Email = *req.Email
+			}
+		}
 		contact.Email = *req.Email
 	}
+	if req.Phones != nil {
+		contact.Phones = models.PhonesFromInput(*req.Phones)
+		contact.Phone = ""
+	}
 	if req.Phone != nil {
+		phones := contact.Phones[:0]
+		for _, phone := range contact.Phones {
+			if phone.Primary && req.Phones == nil {
+				phone.Number = *req.Phone
+			}
+			if phone.Number != "" {
+				phones = append(phones, phone)
+			}
+		}
+		contact.Phones = phones
 		contact.Phone = *req.Phone
 	}
-	if req.Company != nil {
-		contact.Company = *req.Company
+
+	if err := prepareContactMethods(&contact); err != nil {
+		return nil, err
 	}
 
-	if err := s.db.Save(&contact).Error; err != nil {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := checkEmailsAvailable(tx, contact.ID, contact.Emails); err != nil {
+			return err
+		}
+		if req.CompanyID != nil || req.Company != nil {
+			if err := resolveCompany(tx, &contact, req.CompanyID); err != nil {
+				return err
+			}
+		}
+		if err := tx.Omit("Tags", "Lists", "Emails", "Phones", "Addresses", "Dates").Save(&contact).Error; err != nil {
+			return err
+		}
+		if err := replaceContactMethods(tx, &contact); err != nil {
+			return err
+		}
+		if err := replaceNameWords(tx, contact.ID, contact.Name); err != nil {
+			return err
+		}
+		return queueIndexUpdates(tx, s.index, contact.ID)
+	})
+	if err != nil {
 		return nil, err
 	}
+	refreshSearchIndex(s.db, s.index, contact.ID)
 
-	return &contact, nil
+	return s.GetContact(contact.ID)
 }
 
 func (s *ContactService) DeleteContact(id uint) error {
-	return s.db.Delete(&models.Contact{}, id).Error
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := deleteContactRelationships(tx, id); err != nil {
+			return err
+		}
+		if err := replaceNameWords(tx, id, ""); err != nil {
+			return err
+		}
+		if err := tx.Delete(&models.Contact{}, id).Error; err != nil {
+			return err
+		}
+		return queueIndexUpdates(tx, s.index, id)
+	})
+	if err != nil {
+		return err
+	}
+
+	refreshSearchIndex(s.db, s.index, id)
+	return nil
 }
 
-func (s *ContactService) SearchContacts(query string, page, limit int) ([]models.Contact, int64, error) {
+// SearchContacts returns the contacts matching a text query, most relevant
+// first, with the relevance of each one in Contact.Score
+func (s *ContactService) SearchContacts(opts SearchOptions, page, limit int, filter ContactFilter) ([]models.Contact, int64, error) {
+	matches, err := s.scoredMatches(opts, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	if matches != nil {
+		return s.pageMatches(matches, opts, page, limit, filter)
+	}
+
 	var contacts []models.Contact
 	var total int64
 
-	// Build search query
-	searchQuery := s.db.Model(&models.Contact{}).Where(
-		"LOWER(name) LIKE ? OR LOWER(email) LIKE ? OR LOWER(company) LIKE ?",
-		"%"+strings.ToLower(query)+"%",
-		"%"+strings.ToLower(query)+"%",
-		"%"+strings.ToLower(query)+"%",
-	)
+	search := newSearch(s.db, opts)
+	err = search.session(s.db, func(tx *gorm.DB) error {
+		searchQuery := s.searchQuery(tx, search, filter)
 
-	// Count total matching records
-	if err := searchQuery.Count(&total).Error; err != nil {
-		return nil, 0, err
-	}
+		// Count total matching records
+		if err := searchQuery.Count(&total).Error; err != nil {
+			return err
+		}
 
-	// Get paginated results
-	offset := (page - 1) * limit
-	if err := searchQuery.Offset(offset).Limit(limit).Order("created_at DESC").Find(&contacts).Error; err != nil {
+		// Get paginated results
+		offset := (page - 1) * limit
+		sort := filter.sortOr(DefaultSearchSort)
+		ranked := applySort(search.rank(searchQuery, filter.Fields.columns(sort, opts.columns()...)), sort, search != nil)
+		if err := ranked.Scopes(filter.Fields.preload).Offset(offset).Limit(limit).Find(&contacts).Error; err != nil {
+			return err
+		}
+		return filter.Fields.load(tx, contacts)
+	})
+	if err != nil {
 		return nil, 0, err
 	}
+	if opts.Highlight {
+		highlightContacts(opts, contacts)
+	}
 
 	return contacts, total, nil
 }
+
+// CountSearchResults counts the contacts SearchContacts would match
+func (s *ContactService) CountSearchResults(opts SearchOptions, filter ContactFilter) (int64, error) {
+	matches, err := s.scoredMatches(opts, filter)
+	if err != nil {
+		return 0, err
+	}
+	if matches != nil {
+		var total int64
+		err := s.eachMatch(matches, filter, func(batch []scoredMatch) error {
+			total += int64(len(batch))
+			return nil
+		})
+		return total, err
+	}
+
+	var total int64
+	search := newSearch(s.db, opts)
+	err = search.session(s.db, func(tx *gorm.DB) error {
+		return s.searchQuery(tx, search, filter).Count(&total).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// searchQuery builds the query shared by SearchContacts and CountSearchResults.
+// Without a text search it matches every contact allowed by the filter.
+func (s *ContactService) searchQuery(db *gorm.DB, search *textSearch, filter ContactFilter) *gorm.DB {
+	searchQuery := search.apply(db.Model(&models.Contact{}))
+	return s.applyFilter(searchQuery, filter).Session(&gorm.Session{})
+}
+
+// narrows reports whether the filter leaves out any contact
+func (f ContactFilter) narrows() bool {
+	return len(f.Tags) > 0 || len(f.CustomFields) > 0 || f.CompanyID != nil || f.ListID != nil ||
+		len(f.Conditions) > 0 || len(f.IDs) > 0 || f.Company != "" || f.EmailDomain != "" || f.HasPhone != nil ||
+		f.CreatedAfter != nil || f.CreatedBefore != nil || f.UpdatedAfter != nil || f.UpdatedBefore != nil ||
+		f.Expression != nil
+}
+
+// sortOr returns the requested sort, or fallback when there is none
+func (f ContactFilter) sortOr(fallback []SortField) []SortField {
+	if len(f.Sort) == 0 {
+		return fallback
+	}
+	return f.Sort
+}
+
+// applyFilter adds the ContactFilter conditions to a contacts query
+func (s *ContactService) applyFilter(query *gorm.DB, filter ContactFilter) *gorm.DB {
+	if len(filter.Tags) > 0 {
+		tagged := s.db.Table("contact_tags").
+			Select("contact_tags.contact_id").
+			Joins("JOIN tags ON tags.id = contact_tags.tag_id").
+			Where("tags.name IN ?", filter.Tags)
+
+		// Require every requested tag instead of any of them
+		if filter.TagMode == TagMatchAll {
+			tagged = tagged.Group("contact_tags.contact_id").Having("COUNT(DISTINCT tags.id) = ?", len(filter.Tags))
+		}
+
+		query = query.Where("contacts.id IN (?)", tagged)
+	}
+
+	if filter.CompanyID != nil {
+		query = query.Where("contacts.company_id = ?", *filter.CompanyID)
+	}
+
+	if len(filter.IDs) > 0 {
+		query = query.Where("contacts.id IN ?", filter.IDs)
+	}
+
+	// Company names are compared like the stored folded copy
+	if filter.Company != "" {
+		query = query.Where("contacts.company_key = ?", models.FoldText(filter.Company))
+	}
+
+	// Any of the contact's emails may be on the domain
+	if filter.EmailDomain != "" {
+		addresses := s.db.Table("contact_emails").
+			Select("contact_id").
+			Where("LOWER(email) LIKE ?", "%@"+strings.ToLower(filter.EmailDomain))
+		query = query.Where("contacts.id IN (?)", addresses)
+	}
+
+	if filter.HasPhone != nil {
+		if *filter.HasPhone {
+			query = query.Where("contacts.phone IS NOT NULL AND contacts.phone <> ''")
+		} else {
+			query = query.Where("contacts.phone IS NULL OR contacts.phone = ''")
+		}
+	}
+
+	if filter.CreatedAfter != nil {
+		query = query.Where("contacts.created_at >= ?", *filter.CreatedAfter)
+	}
+	if filter.CreatedBefore != nil {
+		query = query.Where("contacts.created_at < ?", *filter.CreatedBefore)
+	}
+	if filter.UpdatedAfter != nil {
+		query = query.Where("contacts.updated_at >= ?", *filter.UpdatedAfter)
+	}
+	if filter.UpdatedBefore != nil {
+		query = query.Where("contacts.updated_at < ?", *filter.UpdatedBefore)
+	}
+
+	if filter.ListID != nil {
+		members := s.db.Table("contact_list_members").
+			Select("contact_id").
+			Where("contact_list_id = ?", *filter.ListID)
+		query = query.Where("contacts.id IN (?)", members)
+	}
+
+	for _, field := range filter.CustomFields {
+		query = query.Where(customFieldCondition(s.db, field))
+	}
+
+	for _, condition := range filter.Conditions {
+		query = query.Where(conditionClause(condition))
+	}
+
+	if filter.Expression != nil {
+		clause, args := expressionClause(filter.Expression)
+		query = query.Where(clause, args...)
+	}
+
+	return query
+}
+
+// customFieldCondition compares a custom field value stored in the JSON
+// column, which both PostgreSQL and SQLite expose through the ->> operator
+func customFieldCondition(db *gorm.DB, field CustomFieldFilter) (string, string, any) {
+	switch field.Type {
+	case models.CustomFieldNumber:
+		number, _ := strconv.ParseFloat(field.Value, 64)
+		if isPostgres(db) {
+			return "CAST(contacts.custom_fields ->> CAST(? AS TEXT) AS DOUBLE PRECISION) = ?", field.Name, number
+		}
+		return "CAST(contacts.custom_fields ->> CAST(? AS TEXT) AS REAL) = ?", field.Name, number
+	case models.CustomFieldBool:
+		// SQLite extracts JSON booleans as 1 and 0
+		if !isPostgres(db) {
+			value := "0"
+			if field.Value == "true" {
+				value = "1"
+			}
+			return "CAST(contacts.custom_fields ->> CAST(? AS TEXT) AS TEXT) = ?", field.Name, value
+		}
+	}
+	return "CAST(contacts.custom_fields ->> CAST(? AS TEXT) AS TEXT) = ?", field.Name, field.Value
+}
+
+// preloadAssociations loads the relations rendered by Contact.ToResponse
+func preloadAssociations(db *gorm.DB) *gorm.DB {
+	return (*FieldSet)(nil).preload(db)
+}
+
+// BackfillPhoneticKeys computes the phonetic key of contacts saved before it
+// existed, returning how many were updated
+func (s *ContactService) BackfillPhoneticKeys() (int, error) {
+	var contacts []models.Contact
+	updated := 0
+
+	err := s.db.Select("id", "name").Where("phonetic_key = '' AND name <> ''").
+		FindInBatches(&contacts, 500, func(tx *gorm.DB, batch int) error {
+			for _, contact := range contacts {
+				key := models.PhoneticKey(contact.Name)
+				if key == "" {
+					continue
+				}
+				if err := s.db.Model(&models.Contact{}).Where("id = ?", contact.ID).UpdateColumn("phonetic_key", key).Error; err != nil {
+					return err
+				}
+				updated++
+			}
+			return nil
+		}).Error
+	if err != nil {
+		return updated, err
+	}
+	return updated, nil
+}
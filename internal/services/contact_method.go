@@ -0,0 +1,225 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"api-contacts-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrEmailTaken      = errors.New("email already in use")
+	ErrMultiplePrimary = errors.New("only one email and one phone can be primary")
+	ErrEmailRequired   = errors.New("a contact must keep at least one email")
+)
+
+// resolveEmails merges the scalar primary email into the labeled list,
+// drops duplicates and guarantees exactly one primary entry
+func resolveEmails(primary string, emails []models.ContactEmail) ([]models.ContactEmail, error) {
+	resolved := make([]models.ContactEmail, 0, len(emails)+1)
+	seen := make(map[string]bool, len(emails)+1)
+	flagged := 0
+
+	for _, email := range emails {
+		email.Email = strings.TrimSpace(email.Email)
+		key := strings.ToLower(email.Email)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		if email.Label == "" {
+			email.Label = models.LabelOther
+		}
+		if email.Primary {
+			flagged++
+		}
+		resolved = append(resolved, email)
+	}
+
+	if flagged > 1 {
+		return nil, ErrMultiplePrimary
+	}
+
+	// The scalar email always wins over a primary flag in the list
+	primary = strings.TrimSpace(primary)
+	if primary != "" && !seen[strings.ToLower(primary)] {
+		resolved = append([]models.ContactEmail{{Email: primary, Label: models.LabelOther}}, resolved...)
+	}
+
+	if len(resolved) == 0 {
+		return nil, ErrEmailRequired
+	}
+
+	primaryIndex := 0
+	for i, email := range resolved {
+		if primary != "" && strings.EqualFold(email.Email, primary) || primary == "" && email.Primary {
+			primaryIndex = i
+			break
+		}
+	}
+	for i := range resolved {
+		resolved[i].Primary = i == primaryIndex
+	}
+
+	return resolved, nil
+}
+
+// resolvePhones mirrors resolveEmails for phone numbers, which are optional
+func resolvePhones(primary string, phones []models.ContactPhone) ([]models.ContactPhone, error) {
+	resolved := make([]models.ContactPhone, 0, len(phones)+1)
+	seen := make(map[string]bool, len(phones)+1)
+	flagged := 0
+
+	for _, phone := range phones {
+		phone.Number = strings.TrimSpace(phone.Number)
+		if phone.Number == "" || seen[phone.Number] {
+			continue
+		}
+		seen[phone.Number] = true
+
+		if phone.Label == "" {
+			phone.Label = models.LabelOther
+		}
+		if phone.Primary {
+			flagged++
+		}
+		resolved = append(resolved, phone)
+	}
+
+	if flagged > 1 {
+		return nil, ErrMultiplePrimary
+	}
+
+	primary = strings.TrimSpace(primary)
+	if primary != "" && !seen[primary] {
+		resolved = append([]models.ContactPhone{{Number: primary, Label: models.LabelOther}}, resolved...)
+	}
+
+	primaryIndex := 0
+	for i, phone := range resolved {
+		if primary != "" && phone.Number == primary || primary == "" && phone.Primary {
+			primaryIndex = i
+			break
+		}
+	}
+	for i := range resolved {
+		resolved[i].Primary = i == primaryIndex
+	}
+
+	return resolved, nil
+}
+
+// prepareContactMethods resolves the contact's emails and phones and mirrors
+// the primary entries into the scalar Email and Phone columns
+func prepareContactMethods(contact *models.Contact) error {
+	emails, err := resolveEmails(contact.Email, contact.Emails)
+	if err != nil {
+		return err
+	}
+
+	phones, err := resolvePhones(contact.Phone, contact.Phones)
+	if err != nil {
+		return err
+	}
+
+	contact.Emails = emails
+	contact.Phones = phones
+	contact.Email = ""
+	contact.Phone = ""
+	for _, email := range emails {
+		if email.Primary {
+			contact.Email = email.Email
+		}
+	}
+	for _, phone := range phones {
+		if phone.Primary {
+			contact.Phone = phone.Number
+		}
+	}
+	contact.MethodsKey = methodsKey(emails, phones)
+
+	return nil
+}
+
+// methodsKey lists the secondary emails and the digits of every phone number
+// of a contact, which migration 000019 adds to the search vector
+func methodsKey(emails []models.ContactEmail, phones []models.ContactPhone) string {
+	words := make([]string, 0, len(emails)+len(phones))
+	for _, email := range emails {
+		if !email.Primary {
+			words = append(words, strings.ToLower(email.Email))
+		}
+	}
+	for _, phone := range phones {
+		if digits := models.PhoneDigits(phone.Number); digits != "" {
+			words = append(words, digits)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// checkEmailsAvailable rejects emails already used by any other contact,
+// including soft-deleted ones since the unique indexes still cover them
+func checkEmailsAvailable(tx *gorm.DB, contactID uint, emails []models.ContactEmail) error {
+	addresses := make([]string, 0, len(emails))
+	for _, email := range emails {
+		addresses = append(addresses, strings.ToLower(email.Email))
+	}
+
+	var count int64
+	if err := tx.Model(&models.ContactEmail{}).
+		Where("LOWER(email) IN ? AND contact_id <> ?", addresses, contactID).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrEmailTaken
+	}
+
+	if err := tx.Unscoped().Model(&models.Contact{}).
+		Where("LOWER(email) IN ? AND id <> ?", addresses, contactID).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrEmailTaken
+	}
+
+	return nil
+}
+
+// replaceContactMethods swaps the stored emails and phones of a contact for
+// the ones currently set on it
+func replaceContactMethods(tx *gorm.DB, contact *models.Contact) error {
+	if err := tx.Where("contact_id = ?", contact.ID).Delete(&models.ContactEmail{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("contact_id = ?", contact.ID).Delete(&models.ContactPhone{}).Error; err != nil {
+		return err
+	}
+
+	for i := range contact.Emails {
+		contact.Emails[i].ID = 0
+		contact.Emails[i].ContactID = contact.ID
+	}
+	for i := range contact.Phones {
+		contact.Phones[i].ID = 0
+		contact.Phones[i].ContactID = contact.ID
+	}
+
+	if len(contact.Emails) > 0 {
+		if err := tx.Create(&contact.Emails).Error; err != nil {
+			return err
+		}
+	}
+	if len(contact.Phones) > 0 {
+		if err := tx.Create(&contact.Phones).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,224 @@
+package services
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"api-contacts-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor marks the position of a contact in a sorted listing. Values holds
+// the contact's sort keys followed by its id. Before selects the page ending
+// at the position instead of the one starting after it.
+type Cursor struct {
+	Sort   string `json:"s"`
+	Values []any  `json:"v"`
+	Before bool   `json:"b,omitempty"`
+}
+
+// Encode renders the cursor as an opaque URL-safe string
+func (c *Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor reads a cursor produced by Encode, which must have been made
+// for the same sort
+func DecodeCursor(value string, sort []SortField) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor Cursor
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&cursor); err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	keys := keysetFields(sort)
+	if cursor.Sort != FormatSort(sort) || len(cursor.Values) != len(keys) {
+		return nil, ErrInvalidCursor
+	}
+
+	// Restore the type of each key, lost in JSON
+	for i, key := range keys {
+		switch key.Field {
+		case "id":
+			number, ok := cursor.Values[i].(json.Number)
+			if !ok {
+				return nil, ErrInvalidCursor
+			}
+			id, err := number.Int64()
+			if err != nil {
+				return nil, ErrInvalidCursor
+			}
+			cursor.Values[i] = id
+		case "created_at", "updated_at":
+			text, ok := cursor.Values[i].(string)
+			if !ok {
+				return nil, ErrInvalidCursor
+			}
+			t, err := time.Parse(time.RFC3339Nano, text)
+			if err != nil {
+				return nil, ErrInvalidCursor
+			}
+			cursor.Values[i] = t
+		default:
+			if _, ok := cursor.Values[i].(string); !ok {
+				return nil, ErrInvalidCursor
+			}
+		}
+	}
+
+	return &cursor, nil
+}
+
+// keysetFields are the sort fields ending with the id tie-break, which
+// together identify a position in the order applySort gives
+func keysetFields(sort []SortField) []SortField {
+	keys := make([]SortField, 0, len(sort)+1)
+	desc := false
+	for _, field := range sort {
+		keys = append(keys, field)
+		desc = field.Desc
+		if field.Field == "id" {
+			return keys
+		}
+	}
+	return append(keys, SortField{Field: "id", Desc: desc})
+}
+
+// cursorFor records the position of a contact in the sort
+func cursorFor(contact models.Contact, sort []SortField, before bool) *Cursor {
+	keys := keysetFields(sort)
+	values := make([]any, 0, len(keys))
+	for _, key := range keys {
+		switch key.Field {
+		case "name":
+			values = append(values, contact.NameKey)
+		case "email":
+			values = append(values, strings.ToLower(contact.Email))
+		case "company":
+			values = append(values, contact.CompanyKey)
+		case "created_at":
+			values = append(values, contact.CreatedAt)
+		case "updated_at":
+			values = append(values, contact.UpdatedAt)
+		default:
+			values = append(values, contact.ID)
+		}
+	}
+	return &Cursor{Sort: FormatSort(sort), Values: values, Before: before}
+}
+
+// afterCursor restricts a query to the contacts past the cursor in the
+// order of keys. Keys sorted in one direction compare as a row, (a, b) > (?, ?),
+// which PostgreSQL reads as the start of a range on the matching index; mixed
+// directions expand to (a > ?) OR (a = ? AND b < ?) OR ...
+func afterCursor(query *gorm.DB, keys []SortField, values []any) *gorm.DB {
+	desc := keys[0].Desc
+	if !slices.ContainsFunc(keys, func(key SortField) bool { return key.Desc != desc }) {
+		columns := make([]string, len(keys))
+		marks := make([]string, len(keys))
+		for i, key := range keys {
+			columns[i] = sortColumn(query, key.Field)
+			marks[i] = "?"
+		}
+		op := " > "
+		if desc {
+			op = " < "
+		}
+		return query.Where("("+strings.Join(columns, ", ")+")"+op+"("+strings.Join(marks, ", ")+")", values...)
+	}
+
+	var clauses []string
+	var args []any
+	for i, key := range keys {
+		var parts []string
+		for j := 0; j < i; j++ {
+			parts = append(parts, sortColumn(query, keys[j].Field)+" = ?")
+			args = append(args, values[j])
+		}
+
+		op := " > ?"
+		if key.Desc {
+			op = " < ?"
+		}
+		parts = append(parts, sortColumn(query, key.Field)+op)
+		args = append(args, values[i])
+		clauses = append(clauses, "("+strings.Join(parts, " AND ")+")")
+	}
+	return query.Where("("+strings.Join(clauses, " OR ")+")", args...)
+}
+
+// reversed flips the direction of every sort field
+func reversed(sort []SortField) []SortField {
+	flipped := make([]SortField, len(sort))
+	for i, field := range sort {
+		flipped[i] = SortField{Field: field.Field, Desc: !field.Desc}
+	}
+	return flipped
+}
+
+// GetContactsByCursor returns the page of contacts next to the cursor in the
+// filter's sort, or the first page without one, along with the cursors of the
+// adjacent pages. Unlike GetContacts it neither counts nor skips rows, so it
+// stays fast on large tables and is not shifted by concurrent inserts.
+func (s *ContactService) GetContactsByCursor(cursor *Cursor, limit int, filter ContactFilter) ([]models.Contact, *Cursor, *Cursor, error) {
+	sort := filter.sortOr(DefaultContactSort)
+	keys := keysetFields(sort)
+	before := cursor != nil && cursor.Before
+
+	// Pages before the cursor are read backwards, then put back in order
+	order := keys
+	if before {
+		order = reversed(keys)
+	}
+
+	query := s.applyFilter(s.db.Model(&models.Contact{}), filter)
+	if cursor != nil {
+		query = afterCursor(query, order, cursor.Values)
+	}
+
+	var contacts []models.Contact
+	paged := filter.Fields.selectColumns(applySort(query, order, false), order)
+	if err := paged.Scopes(filter.Fields.preload).Limit(limit + 1).Find(&contacts).Error; err != nil {
+		return nil, nil, nil, err
+	}
+
+	more := len(contacts) > limit
+	if more {
+		contacts = contacts[:limit]
+	}
+	if before {
+		slices.Reverse(contacts)
+	}
+	if err := filter.Fields.load(s.db, contacts); err != nil {
+		return nil, nil, nil, err
+	}
+	if len(contacts) == 0 {
+		return contacts, nil, nil, nil
+	}
+
+	// Reading forwards, the page came after another one when there is a
+	// cursor; reading backwards, it came before the cursor's page
+	var next, prev *Cursor
+	if more || before {
+		next = cursorFor(contacts[len(contacts)-1], sort, false)
+	}
+	if before && more || !before && cursor != nil {
+		prev = cursorFor(contacts[0], sort, true)
+	}
+	return contacts, next, prev, nil
+}
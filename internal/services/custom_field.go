@@ -0,0 +1,320 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"api-contacts-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCustomFieldNotFound = errors.New("custom field not found")
+	ErrCustomFieldExists   = errors.New("custom field already exists")
+	ErrEnumValuesNotEnum   = errors.New("enum_values only apply to enum fields")
+)
+
+// CustomFieldError lists every problem found in a contact's custom field values
+type CustomFieldError struct {
+	Problems []string
+}
+
+func (e *CustomFieldError) Error() string {
+	return strings.Join(e.Problems, "; ")
+}
+
+// CustomFieldFilter matches contacts whose custom field equals a value
+type CustomFieldFilter struct {
+	Name  string
+	Type  string
+	Value string
+}
+
+type CustomFieldService struct {
+	db *gorm.DB
+}
+
+func NewCustomFieldService(db *gorm.DB) *CustomFieldService {
+	return &CustomFieldService{db: db}
+}
+
+func (s *CustomFieldService) GetCustomFields() ([]models.CustomFieldDefinition, error) {
+	fields := []models.CustomFieldDefinition{}
+	if err := s.db.Order("name").Find(&fields).Error; err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+func (s *CustomFieldService) GetCustomField(id uint) (*models.CustomFieldDefinition, error) {
+	var field models.CustomFieldDefinition
+	if err := s.db.First(&field, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrCustomFieldNotFound
+		}
+		return nil, err
+	}
+	return &field, nil
+}
+
+func (s *CustomFieldService) CreateCustomField(req *models.CreateCustomFieldRequest) (*models.CustomFieldDefinition, error) {
+	var count int64
+	if err := s.db.Model(&models.CustomFieldDefinition{}).Where("name = ?", req.Name).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, ErrCustomFieldExists
+	}
+
+	field := models.CustomFieldDefinition{
+		Name:     req.Name,
+		Label:    req.Label,
+		Type:     req.Type,
+		Required: req.Required,
+	}
+	if req.Type == models.CustomFieldEnum {
+		field.EnumValues = req.EnumValues
+	} else if len(req.EnumValues) > 0 {
+		return nil, ErrEnumValuesNotEnum
+	}
+
+	if err := s.db.Create(&field).Error; err != nil {
+		return nil, err
+	}
+
+	return &field, nil
+}
+
+func (s *CustomFieldService) UpdateCustomField(id uint, req *models.UpdateCustomFieldRequest) (*models.CustomFieldDefinition, error) {
+	field, err := s.GetCustomField(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Name and type are fixed once values may have been stored
+	if req.Label != nil {
+		field.Label = *req.Label
+	}
+	if req.Required != nil {
+		field.Required = *req.Required
+	}
+	if req.EnumValues != nil {
+		if field.Type != models.CustomFieldEnum {
+			return nil, ErrEnumValuesNotEnum
+		}
+		field.EnumValues = *req.EnumValues
+	}
+
+	if err := s.db.Save(field).Error; err != nil {
+		return nil, err
+	}
+
+	return field, nil
+}
+
+// DeleteCustomField removes the definition along with the values stored on contacts
+func (s *CustomFieldService) DeleteCustomField(id uint) error {
+	field, err := s.GetCustomField(id)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var remove any
+		if isPostgres(tx) {
+			remove = gorm.Expr("custom_fields - ?", field.Name)
+		} else {
+			remove = gorm.Expr("json_remove(custom_fields, ?)", `$."`+field.Name+`"`)
+		}
+
+		if err := tx.Unscoped().Model(&models.Contact{}).
+			Where("custom_fields IS NOT NULL").
+			UpdateColumn("custom_fields", remove).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(field).Error
+	})
+}
+
+// Definitions returns the registered custom fields indexed by name
+func (s *CustomFieldService) Definitions() (map[string]models.CustomFieldDefinition, error) {
+	fields, err := s.GetCustomFields()
+	if err != nil {
+		return nil, err
+	}
+
+	definitions := make(map[string]models.CustomFieldDefinition, len(fields))
+	for _, field := range fields {
+		definitions[field.Name] = field
+	}
+	return definitions, nil
+}
+
+// ValidateValues checks custom field values against their definitions. On a
+// partial update missing required fields are allowed and null values clear
+// the stored one; otherwise every required field must be present.
+func (s *CustomFieldService) ValidateValues(values map[string]any, partial bool) error {
+	definitions, err := s.Definitions()
+	if err != nil {
+		return err
+	}
+
+	var problems []string
+	for name, value := range values {
+		definition, ok := definitions[name]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("custom field '%s' is not defined", name))
+			continue
+		}
+
+		if value == nil {
+			if definition.Required {
+				problems = append(problems, fmt.Sprintf("custom field '%s' is required", name))
+			}
+			continue
+		}
+
+		if problem := checkCustomFieldValue(definition, value); problem != "" {
+			problems = append(problems, problem)
+		}
+	}
+
+	if !partial {
+		for name, definition := range definitions {
+			if _, ok := values[name]; definition.Required && !ok {
+				problems = append(problems, fmt.Sprintf("custom field '%s' is required", name))
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return &CustomFieldError{Problems: problems}
+	}
+	return nil
+}
+
+// BuildFilters turns "name=value" query pairs into custom field filters,
+// rejecting names that are not defined and values of the wrong type
+func (s *CustomFieldService) BuildFilters(values map[string]string) ([]CustomFieldFilter, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	definitions, err := s.Definitions()
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []string
+	filters := make([]CustomFieldFilter, 0, len(values))
+	for name, value := range values {
+		definition, ok := definitions[name]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("custom field '%s' is not defined", name))
+			continue
+		}
+
+		problem := ""
+		switch definition.Type {
+		case models.CustomFieldNumber:
+			if _, err := strconv.ParseFloat(value, 64); err != nil {
+				problem = fmt.Sprintf("custom field '%s' must be a number", name)
+			}
+		case models.CustomFieldBool:
+			if value != "true" && value != "false" {
+				problem = fmt.Sprintf("custom field '%s' must be true or false", name)
+			}
+		default:
+			problem = checkCustomFieldValue(definition, value)
+		}
+		if problem != "" {
+			problems = append(problems, problem)
+			continue
+		}
+
+		filters = append(filters, CustomFieldFilter{Name: name, Type: definition.Type, Value: value})
+	}
+
+	if len(problems) > 0 {
+		return nil, &CustomFieldError{Problems: problems}
+	}
+	return filters, nil
+}
+
+// ParseValues types custom field values written as text, such as CSV cells,
+// after their definitions. Values that do not parse are kept as text for
+// ValidateValues to report.
+func (s *CustomFieldService) ParseValues(values map[string]string) (map[string]any, error) {
+	definitions, err := s.Definitions()
+	if err != nil {
+		return nil, err
+	}
+
+	parsed := make(map[string]any, len(values))
+	for name, value := range values {
+		parsed[name] = value
+		switch definitions[name].Type {
+		case models.CustomFieldNumber:
+			if number, err := strconv.ParseFloat(value, 64); err == nil {
+				parsed[name] = number
+			}
+		case models.CustomFieldBool:
+			if flag, err := strconv.ParseBool(value); err == nil {
+				parsed[name] = flag
+			}
+		}
+	}
+	return parsed, nil
+}
+
+// mergeCustomFields applies a partial update where null values remove the key
+func mergeCustomFields(current models.JSONMap, changes map[string]any) models.JSONMap {
+	merged := models.JSONMap{}
+	for name, value := range current {
+		merged[name] = value
+	}
+	for name, value := range changes {
+		if value == nil {
+			delete(merged, name)
+			continue
+		}
+		merged[name] = value
+	}
+	return merged
+}
+
+func checkCustomFieldValue(definition models.CustomFieldDefinition, value any) string {
+	switch definition.Type {
+	case models.CustomFieldString:
+		if _, ok := value.(string); !ok {
+			return fmt.Sprintf("custom field '%s' must be a string", definition.Name)
+		}
+	case models.CustomFieldNumber:
+		if _, ok := value.(float64); !ok {
+			return fmt.Sprintf("custom field '%s' must be a number", definition.Name)
+		}
+	case models.CustomFieldBool:
+		if _, ok := value.(bool); !ok {
+			return fmt.Sprintf("custom field '%s' must be a boolean", definition.Name)
+		}
+	case models.CustomFieldDate:
+		text, ok := value.(string)
+		if _, err := time.Parse(models.CustomFieldDateLayout, text); !ok || err != nil {
+			return fmt.Sprintf("custom field '%s' must be a date formatted as YYYY-MM-DD", definition.Name)
+		}
+	case models.CustomFieldEnum:
+		text, _ := value.(string)
+		for _, option := range definition.EnumValues {
+			if text == option {
+				return ""
+			}
+		}
+		return fmt.Sprintf("custom field '%s' must be one of: %s", definition.Name, strings.Join(definition.EnumValues, ", "))
+	}
+	return ""
+}
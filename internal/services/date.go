@@ -0,0 +1,224 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"api-contacts-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+var ErrDateNotFound = errors.New("date not found")
+
+type DateService struct {
+	db *gorm.DB
+}
+
+func NewDateService(db *gorm.DB) *DateService {
+	return &DateService{db: db}
+}
+
+func (s *DateService) GetDates(contactID uint) ([]models.SignificantDate, error) {
+	var contact models.Contact
+	if err := s.db.First(&contact, contactID).Error; err != nil {
+		return nil, err
+	}
+
+	dates := []models.SignificantDate{}
+	if err := s.db.Where("contact_id = ?", contactID).Order("month, day, id").Find(&dates).Error; err != nil {
+		return nil, err
+	}
+
+	return dates, nil
+}
+
+func (s *DateService) CreateDate(contactID uint, req *models.SignificantDateRequest) (*models.SignificantDate, error) {
+	var contact models.Contact
+	if err := s.db.First(&contact, contactID).Error; err != nil {
+		return nil, err
+	}
+
+	date, err := models.ParsePartialDate(req.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	significantDate := models.SignificantDate{ContactID: contactID, Label: req.Label}
+	significantDate.SetDate(date)
+
+	if err := s.db.Create(&significantDate).Error; err != nil {
+		return nil, err
+	}
+
+	return &significantDate, nil
+}
+
+func (s *DateService) UpdateDate(contactID, dateID uint, req *models.SignificantDateRequest) (*models.SignificantDate, error) {
+	significantDate, err := s.findDate(contactID, dateID)
+	if err != nil {
+		return nil, err
+	}
+
+	date, err := models.ParsePartialDate(req.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	significantDate.Label = req.Label
+	significantDate.SetDate(date)
+
+	if err := s.db.Save(significantDate).Error; err != nil {
+		return nil, err
+	}
+
+	return significantDate, nil
+}
+
+func (s *DateService) DeleteDate(contactID, dateID uint) error {
+	significantDate, err := s.findDate(contactID, dateID)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Delete(significantDate).Error
+}
+
+// UpcomingDates lists the birthdays and significant dates falling within
+// the given number of days from today, soonest first
+func (s *DateService) UpcomingDates(from time.Time, days int) ([]models.UpcomingDate, error) {
+	today := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+
+	// Only the months touched by the window can hold upcoming dates
+	seen := map[int]bool{}
+	var months []int
+	for d := 0; d <= days && len(months) < 12; d++ {
+		month := int(today.AddDate(0, 0, d).Month())
+		if !seen[month] {
+			seen[month] = true
+			months = append(months, month)
+		}
+	}
+
+	dates, err := s.contactDates(months)
+	if err != nil {
+		return nil, err
+	}
+
+	upcoming := []models.UpcomingDate{}
+	for _, date := range dates {
+		next := date.Date.NextOccurrence(today)
+		daysUntil := daysBetween(today, next)
+		if daysUntil > days {
+			continue
+		}
+
+		entry := models.UpcomingDate{
+			ContactID:   date.ContactID,
+			ContactName: date.ContactName,
+			Kind:        date.Kind,
+			Label:       date.Label,
+			Date:        next.Format("2006-01-02"),
+			Original:    date.Date.String(),
+			DaysUntil:   daysUntil,
+		}
+		if date.Date.Year != nil && next.Year() > *date.Date.Year {
+			years := next.Year() - *date.Date.Year
+			entry.Years = &years
+		}
+		upcoming = append(upcoming, entry)
+	}
+
+	sort.SliceStable(upcoming, func(i, j int) bool {
+		if upcoming[i].DaysUntil != upcoming[j].DaysUntil {
+			return upcoming[i].DaysUntil < upcoming[j].DaysUntil
+		}
+		return upcoming[i].ContactName < upcoming[j].ContactName
+	})
+
+	return upcoming, nil
+}
+
+// CalendarDates lists every birthday and significant date for the calendar feed
+func (s *DateService) CalendarDates() ([]models.ContactDate, error) {
+	return s.contactDates(nil)
+}
+
+// contactDates loads the birthdays and significant dates of live contacts,
+// restricted to the given months when any are passed
+func (s *DateService) contactDates(months []int) ([]models.ContactDate, error) {
+	birthdays := s.db.Model(&models.Contact{}).Where("birth_month IS NOT NULL AND birth_day IS NOT NULL")
+	if months != nil {
+		birthdays = birthdays.Where("birth_month IN ?", months)
+	}
+
+	var contacts []models.Contact
+	if err := birthdays.Order("id").Find(&contacts).Error; err != nil {
+		return nil, err
+	}
+
+	dates := make([]models.ContactDate, 0, len(contacts))
+	for _, contact := range contacts {
+		dates = append(dates, models.ContactDate{
+			ID:          contact.ID,
+			ContactID:   contact.ID,
+			ContactName: contact.Name,
+			Kind:        models.DateKindBirthday,
+			Label:       models.DateKindBirthday,
+			Date:        *contact.Birthday(),
+		})
+	}
+
+	var rows []struct {
+		models.SignificantDate
+		ContactName string
+	}
+	significant := s.db.Table("significant_dates").
+		Select("significant_dates.*, contacts.name AS contact_name").
+		Joins("JOIN contacts ON contacts.id = significant_dates.contact_id AND contacts.deleted_at IS NULL")
+	if months != nil {
+		significant = significant.Where("significant_dates.month IN ?", months)
+	}
+	if err := significant.Order("significant_dates.id").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		dates = append(dates, models.ContactDate{
+			ID:          row.ID,
+			ContactID:   row.ContactID,
+			ContactName: row.ContactName,
+			Kind:        models.DateKindSignificant,
+			Label:       row.Label,
+			Date:        row.Date(),
+		})
+	}
+
+	return dates, nil
+}
+
+// findDate loads a significant date making sure it belongs to the given contact
+func (s *DateService) findDate(contactID, dateID uint) (*models.SignificantDate, error) {
+	var contact models.Contact
+	if err := s.db.First(&contact, contactID).Error; err != nil {
+		return nil, err
+	}
+
+	var significantDate models.SignificantDate
+	if err := s.db.Where("contact_id = ?", contactID).First(&significantDate, dateID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrDateNotFound
+		}
+		return nil, err
+	}
+
+	return &significantDate, nil
+}
+
+// daysBetween counts calendar days, ignoring daylight saving shifts
+func daysBetween(from, to time.Time) int {
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	end := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(end.Sub(start).Hours() / 24)
+}
@@ -0,0 +1,9 @@
+package services
+
+import "gorm.io/gorm"
+
+// isPostgres reports whether db talks to PostgreSQL rather than the SQLite
+// database used by the tests
+func isPostgres(db *gorm.DB) bool {
+	return db.Dialector.Name() == "postgres"
+}
@@ -0,0 +1,632 @@
+package services
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"api-contacts-go/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// diskIndexVersion changes whenever the stored format does, so that older
+// files are rebuilt instead of misread
+const diskIndexVersion = 2
+
+// compactMinEntries is how many changes the log holds, at the least, before
+// it is folded into a new snapshot
+const compactMinEntries = 1000
+
+// indexDocument is the searchable text of a contact, folded the way searches
+// compare it, with the other fields searches sort by
+type indexDocument struct {
+	Name      string
+	Company   string
+	Email     string
+	Methods   string
+	Phonetic  []string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// diskIndexFile is the content of the snapshot file
+type diskIndexFile struct {
+	Version   int
+	Documents map[uint]indexDocument
+}
+
+// logEntry is a change to one contact in the change log, with a nil Document
+// when the contact was removed
+type logEntry struct {
+	ID       uint           `json:"id"`
+	Document *indexDocument `json:"document,omitempty"`
+}
+
+// DiskIndex is an inverted index of contacts kept in memory. It is stored as
+// a gob snapshot at path and a log of the changes made since, one JSON line
+// each, appended to on every change and folded into a new snapshot in the
+// background once it outgrows the snapshot. Text searches look the words of
+// the query up, phonetic searches look up the sound of each word, while
+// fuzzy and phone number searches scan the stored text.
+//
+// A single process may have the index open, as the updates marked in the
+// database are cleared by the first process applying them: OpenDiskIndex
+// takes a lock on the path and fails with ErrSearchIndexLocked while another
+// process holds it.
+type DiskIndex struct {
+	path string
+	// lock is held open, locked, until the index is closed
+	lock *os.File
+
+	mu        sync.RWMutex
+	documents map[uint]indexDocument
+	// postings maps each word to the contacts having it, with its weight
+	postings map[string]map[uint]float64
+	// sounds maps each phonetic code to the contacts whose name has it
+	sounds map[string]map[uint]bool
+	// log is the open change log, holding logged entries
+	log    *os.File
+	logged int
+	// compactQueued is set from when a compaction is started until it takes
+	// its copy of the documents
+	compactQueued bool
+	// pending collects the changes made while a rebuild loads its entries,
+	// nil when no rebuild runs
+	pending []logEntry
+
+	// compacting is held through a compaction or a rebuild, compactions
+	// tracks the ones started in the background
+	compacting  sync.Mutex
+	compactions sync.WaitGroup
+}
+
+// ErrSearchIndexLocked is returned when opening a DiskIndex another process
+// has open
+var ErrSearchIndexLocked = errors.New("search index is open in another process")
+
+// OpenDiskIndex loads the index stored at path, or starts an empty one when
+// there is none yet or it has an outdated format
+func OpenDiskIndex(path string) (*DiskIndex, error) {
+	index := &DiskIndex{path: path}
+	index.reset()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+
+	lock, err := lockFile(index.lockPath())
+	if err != nil {
+		if errors.Is(err, ErrSearchIndexLocked) {
+			return nil, fmt.Errorf("%w: %s", err, path)
+		}
+		return nil, err
+	}
+	if err := index.open(); err != nil {
+		lock.Close()
+		return nil, err
+	}
+	index.lock = lock
+	return index, nil
+}
+
+// open reads the snapshot and replays the logs, then opens the log for the
+// next changes
+func (x *DiskIndex) open() error {
+	current, err := x.load()
+	if err != nil {
+		return err
+	}
+	if !current {
+		// Logs only make sense on top of their snapshot
+		for _, log := range []string{x.oldLogPath(), x.logPath()} {
+			if err := os.Remove(log); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+		}
+		if err := x.writeSnapshot(x.documents); err != nil {
+			return err
+		}
+	}
+
+	// A compaction cut short leaves the log it was folding in behind
+	interrupted, err := x.replay(x.oldLogPath())
+	if err != nil {
+		return err
+	}
+	if x.logged, err = x.replay(x.logPath()); err != nil {
+		return err
+	}
+	if interrupted > 0 {
+		if err := x.writeSnapshot(x.documents); err != nil {
+			return err
+		}
+	}
+	if err := os.Remove(x.oldLogPath()); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return x.openLog()
+}
+
+// Empty reports whether the index has no contacts, as when it was just created
+func (x *DiskIndex) Empty() bool {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	return len(x.documents) == 0
+}
+
+func (x *DiskIndex) Index(contacts ...models.Contact) error {
+	if len(contacts) == 0 {
+		return nil
+	}
+
+	entries := make([]logEntry, len(contacts))
+	for i, contact := range contacts {
+		document := newIndexDocument(contact)
+		entries[i] = logEntry{ID: contact.ID, Document: &document}
+	}
+	return x.change(entries)
+}
+
+func (x *DiskIndex) Remove(ids ...uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	entries := make([]logEntry, len(ids))
+	for i, id := range ids {
+		entries[i] = logEntry{ID: id}
+	}
+	return x.change(entries)
+}
+
+// Rebuild builds the entries anew and writes them as a new snapshot, which
+// the log is emptied for. Searches and changes go on against the current
+// entries while load runs; the changes made meanwhile are applied again on
+// top of the new entries, which replace the current ones once written.
+func (x *DiskIndex) Rebuild(load func(add func(contacts []models.Contact)) error) error {
+	x.compacting.Lock()
+	defer x.compacting.Unlock()
+
+	x.mu.Lock()
+	x.pending = []logEntry{}
+	x.mu.Unlock()
+
+	fresh := &DiskIndex{}
+	fresh.reset()
+	err := load(func(contacts []models.Contact) {
+		for _, contact := range contacts {
+			fresh.add(contact.ID, newIndexDocument(contact))
+		}
+	})
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	pending := x.pending
+	x.pending = nil
+	if err != nil {
+		return err
+	}
+	for _, entry := range pending {
+		fresh.apply(entry)
+	}
+	if err := x.writeSnapshot(fresh.documents); err != nil {
+		return err
+	}
+	x.documents, x.postings, x.sounds = fresh.documents, fresh.postings, fresh.sounds
+
+	// The new snapshot also covers a log a failed compaction set aside
+	if err := os.Remove(x.oldLogPath()); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if err := x.log.Truncate(0); err != nil {
+		return err
+	}
+	x.logged = 0
+	return x.log.Sync()
+}
+
+// Compact folds the change log into a new snapshot. It runs in the
+// background as the log grows, only holding changes off while the log is
+// set aside for a new one.
+func (x *DiskIndex) Compact() error {
+	x.compacting.Lock()
+	defer x.compacting.Unlock()
+
+	x.mu.Lock()
+	x.compactQueued = false
+	documents := maps.Clone(x.documents)
+	var err error
+	// The log set aside by a failed compaction is still to be folded in, and
+	// the new snapshot covers the current log as well
+	if _, stat := os.Stat(x.oldLogPath()); errors.Is(stat, os.ErrNotExist) {
+		err = x.rotateLog()
+	}
+	x.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if err := x.writeSnapshot(documents); err != nil {
+		return err
+	}
+	return os.Remove(x.oldLogPath())
+}
+
+// Close waits for a running compaction, closes the change log and releases
+// the index to other processes
+func (x *DiskIndex) Close() error {
+	x.compactions.Wait()
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	return errors.Join(x.log.Close(), x.lock.Close())
+}
+
+func (x *DiskIndex) Match(opts SearchOptions, sort []SortField) ([]SearchHit, error) {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	var scores map[uint]float64
+	switch opts.Mode {
+	case SearchModeFuzzy:
+		scores = x.matchFuzzy(opts)
+	case SearchModePhonetic:
+		scores = x.matchPhonetic(opts.Query)
+	default:
+		scores = x.matchText(opts.Query)
+	}
+
+	matches := make([]scoredMatch, 0, len(scores))
+	for id, score := range scores {
+		matches = append(matches, x.documents[id].match(id, score))
+	}
+	sortMatches(matches, sort)
+
+	hits := make([]SearchHit, len(matches))
+	for i, match := range matches {
+		hits[i] = SearchHit{ID: match.id, Score: match.score}
+	}
+	return hits, nil
+}
+
+// matchText looks the words of the query up whole, as the text search of the
+// database does. Queries that look like a phone number also match the phones
+// containing their digits, which takes a scan of every contact.
+func (x *DiskIndex) matchText(query string) map[uint]float64 {
+	parsed := parseTextQuery(query)
+	digits := ""
+	if isPhoneQuery(query) {
+		digits = models.PhoneDigits(query)
+	}
+
+	scores := map[uint]float64{}
+	check := func(id uint) {
+		score, ok := parsed.score(func(word string) (float64, bool) {
+			weight, ok := x.postings[word][id]
+			return weight, ok
+		})
+		if phone := phoneScore(x.documents[id].Methods, digits); phone > 0 {
+			score, ok = score+phone, true
+		}
+		if ok {
+			scores[id] = score
+		}
+	}
+
+	candidates, ok := x.candidates(parsed)
+	if !ok || digits != "" {
+		for id := range x.documents {
+			check(id)
+		}
+		return scores
+	}
+	for id := range candidates {
+		check(id)
+	}
+	return scores
+}
+
+// candidates collects the contacts having a word of the rarest group without
+// negated terms, which every match has. It reports false when every group
+// has negated terms, leaving all contacts to check.
+func (x *DiskIndex) candidates(query textQuery) (map[uint]bool, bool) {
+	var best []queryTerm
+	size := 0
+	for _, group := range query {
+		if slices.ContainsFunc(group, func(term queryTerm) bool { return term.negated }) {
+			continue
+		}
+		total := 0
+		for _, term := range group {
+			total += len(x.postings[term.word])
+		}
+		if best == nil || total < size {
+			best, size = group, total
+		}
+	}
+	if best == nil {
+		return nil, len(query) == 0
+	}
+
+	candidates := make(map[uint]bool, size)
+	for _, term := range best {
+		for id := range x.postings[term.word] {
+			candidates[id] = true
+		}
+	}
+	return candidates, true
+}
+
+// matchPhonetic requires every word of the query to sound like a word of the
+// name, ranking names spelled as queried first
+func (x *DiskIndex) matchPhonetic(query string) map[uint]float64 {
+	scores := map[uint]float64{}
+	codes := strings.Fields(models.PhoneticKey(query))
+	if len(codes) == 0 {
+		return scores
+	}
+
+	for id := range x.sounds[codes[0]] {
+		if slices.ContainsFunc(codes[1:], func(code string) bool { return !x.sounds[code][id] }) {
+			continue
+		}
+		scores[id] = 0.5
+		if strings.Contains(x.documents[id].Name, models.FoldText(strings.TrimSpace(query))) {
+			scores[id] = 1.0
+		}
+	}
+	return scores
+}
+
+// matchFuzzy scores every contact by word similarity, as the fuzzy search
+// does without pg_trgm
+func (x *DiskIndex) matchFuzzy(opts SearchOptions) map[uint]float64 {
+	query := strings.TrimSpace(models.FoldText(opts.Query))
+	scores := map[uint]float64{}
+	for id, document := range x.documents {
+		if score := fuzzyScore(query, document.Name, document.Company, document.Email); score >= opts.Threshold {
+			scores[id] = score
+		}
+	}
+	return scores
+}
+
+func newIndexDocument(contact models.Contact) indexDocument {
+	return indexDocument{
+		Name:      models.FoldText(contact.Name),
+		Company:   models.FoldText(contact.Company),
+		Email:     strings.ToLower(contact.Email),
+		Methods:   contact.MethodsKey,
+		Phonetic:  strings.Fields(models.PhoneticKey(contact.Name)),
+		CreatedAt: contact.CreatedAt,
+		UpdatedAt: contact.UpdatedAt,
+	}
+}
+
+// weights lists the words of a document with the sum of the weights of the
+// fields they appear in
+func (d indexDocument) weights() searchDocument {
+	return newSearchDocument(d.Name, d.Company, d.Email, d.Methods)
+}
+
+// match holds the document's sort fields, the same the contacts table has
+func (d indexDocument) match(id uint, score float64) scoredMatch {
+	return scoredMatch{
+		id:         id,
+		score:      score,
+		nameKey:    d.Name,
+		email:      d.Email,
+		companyKey: d.Company,
+		createdAt:  d.CreatedAt,
+		updatedAt:  d.UpdatedAt,
+	}
+}
+
+func (x *DiskIndex) reset() {
+	x.documents = map[uint]indexDocument{}
+	x.postings = map[string]map[uint]float64{}
+	x.sounds = map[string]map[uint]bool{}
+}
+
+func (x *DiskIndex) add(id uint, document indexDocument) {
+	x.documents[id] = document
+	for word, weight := range document.weights() {
+		if x.postings[word] == nil {
+			x.postings[word] = map[uint]float64{}
+		}
+		x.postings[word][id] = weight
+	}
+	for _, code := range document.Phonetic {
+		if x.sounds[code] == nil {
+			x.sounds[code] = map[uint]bool{}
+		}
+		x.sounds[code][id] = true
+	}
+}
+
+func (x *DiskIndex) drop(id uint) {
+	document, ok := x.documents[id]
+	if !ok {
+		return
+	}
+	delete(x.documents, id)
+	for word := range document.weights() {
+		delete(x.postings[word], id)
+		if len(x.postings[word]) == 0 {
+			delete(x.postings, word)
+		}
+	}
+	for _, code := range document.Phonetic {
+		delete(x.sounds[code], id)
+		if len(x.sounds[code]) == 0 {
+			delete(x.sounds, code)
+		}
+	}
+}
+
+// apply replaces or drops the entry of a logged contact
+func (x *DiskIndex) apply(entry logEntry) {
+	x.drop(entry.ID)
+	if entry.Document != nil {
+		x.add(entry.ID, *entry.Document)
+	}
+}
+
+// change logs entries, flushed to disk, before applying them, starting a
+// compaction once the log outgrows the snapshot
+func (x *DiskIndex) change(entries []logEntry) error {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	for _, entry := range entries {
+		if err := encoder.Encode(entry); err != nil {
+			return err
+		}
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if _, err := x.log.Write(buffer.Bytes()); err != nil {
+		return err
+	}
+	if err := x.log.Sync(); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		x.apply(entry)
+	}
+	if x.pending != nil {
+		x.pending = append(x.pending, entries...)
+	}
+
+	x.logged += len(entries)
+	if x.logged >= max(compactMinEntries, len(x.documents)) && !x.compactQueued {
+		x.compactQueued = true
+		x.compactions.Add(1)
+		go func() {
+			defer x.compactions.Done()
+			if err := x.Compact(); err != nil {
+				logrus.Warnf("Failed to compact search index %s: %v", x.path, err)
+			}
+		}()
+	}
+	return nil
+}
+
+// replay applies the entries of a log, returning how many it had. A line cut
+// short by a crash ends the log, and is cut off so that later entries are
+// not appended after it.
+func (x *DiskIndex) replay(path string) (int, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	decoder := json.NewDecoder(file)
+	replayed := 0
+	var end int64
+	for {
+		var entry logEntry
+		err := decoder.Decode(&entry)
+		if errors.Is(err, io.EOF) {
+			return replayed, nil
+		}
+		if err != nil {
+			return replayed, os.Truncate(path, end)
+		}
+		x.apply(entry)
+		replayed++
+		end = decoder.InputOffset()
+	}
+}
+
+func (x *DiskIndex) logPath() string    { return x.path + ".log" }
+func (x *DiskIndex) oldLogPath() string { return x.path + ".log.old" }
+func (x *DiskIndex) lockPath() string   { return x.path + ".lock" }
+
+func (x *DiskIndex) openLog() error {
+	log, err := os.OpenFile(x.logPath(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	x.log = log
+	return nil
+}
+
+// rotateLog sets the change log aside for a compaction to fold in, and
+// starts a new one
+func (x *DiskIndex) rotateLog() error {
+	// Every write was flushed already
+	x.log.Close()
+	rotated := os.Rename(x.logPath(), x.oldLogPath())
+	if err := x.openLog(); err != nil {
+		return err
+	}
+	if rotated != nil {
+		return rotated
+	}
+	x.logged = 0
+	return nil
+}
+
+// load reads the snapshot, reporting whether there was one in the current
+// format
+func (x *DiskIndex) load() (bool, error) {
+	file, err := os.Open(x.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	var stored diskIndexFile
+	if err := gob.NewDecoder(file).Decode(&stored); err != nil {
+		return false, fmt.Errorf("failed to read search index %s: %w", x.path, err)
+	}
+	if stored.Version != diskIndexVersion {
+		return false, nil
+	}
+	for id, document := range stored.Documents {
+		x.add(id, document)
+	}
+	return true, nil
+}
+
+// writeSnapshot writes the documents to a temporary file renamed over the
+// snapshot, so that a crash never leaves a partial snapshot behind
+func (x *DiskIndex) writeSnapshot(documents map[uint]indexDocument) error {
+	temp, err := os.CreateTemp(filepath.Dir(x.path), filepath.Base(x.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+
+	stored := diskIndexFile{Version: diskIndexVersion, Documents: documents}
+	if err := gob.NewEncoder(temp).Encode(stored); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Sync(); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(temp.Name(), x.path)
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"api-contacts-go/internal/filter"
+	"api-contacts-go/internal/models"
+)
+
+// FilterFields are the contact fields a filter expression may compare. Text
+// and date fields share their columns with the smart list conditions.
+var FilterFields = map[string]filter.FieldType{
+	"id":         filter.FieldNumber,
+	"company_id": filter.FieldNumber,
+	"name":       filter.FieldText,
+	"email":      filter.FieldText,
+	"phone":      filter.FieldText,
+	"company":    filter.FieldText,
+	"created_at": filter.FieldTime,
+	"updated_at": filter.FieldTime,
+}
+
+var expressionNumberFields = map[string]string{
+	"id":         "contacts.id",
+	"company_id": "contacts.company_id",
+}
+
+// nullableFields are the filter fields whose column can be NULL, which no
+// comparison matches: a contact without a company is neither "= 5" nor "!= 5"
+// to SQL, while it is "!= 5" to the filter
+var nullableFields = map[string]bool{
+	"company_id": true,
+	"phone":      true,
+}
+
+// likeEscaper keeps LIKE wildcards typed by the user literal
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// expressionClause translates a parsed filter expression into a WHERE clause.
+// Column names only come from the whitelists and every value is a bound
+// parameter.
+func expressionClause(expr filter.Expr) (string, []any) {
+	switch e := expr.(type) {
+	case *filter.Logical:
+		left, leftArgs := expressionClause(e.Left)
+		right, rightArgs := expressionClause(e.Right)
+		return fmt.Sprintf("(%s %s %s)", left, strings.ToUpper(e.Op), right), append(leftArgs, rightArgs...)
+	case *filter.Not:
+		clause, args := expressionClause(e.Expr)
+		if comparesNullable(e.Expr) {
+			// NOT keeps the unknown result of comparing NULL unknown, so it
+			// counts as false before being negated
+			clause = "COALESCE(" + clause + ", FALSE)"
+		}
+		return "NOT " + clause, args
+	case *filter.Comparison:
+		switch e.Type {
+		case filter.FieldNumber:
+			return numberComparison(expressionNumberFields[e.Field], e)
+		case filter.FieldTime:
+			return timeComparison(conditionDateFields[e.Field], e)
+		}
+		return textComparison(conditionTextFields[e.Field], e)
+	}
+	panic(fmt.Sprintf("unexpected filter node %T", expr))
+}
+
+// comparesNullable reports whether an expression compares a nullable field
+func comparesNullable(expr filter.Expr) bool {
+	switch e := expr.(type) {
+	case *filter.Logical:
+		return comparesNullable(e.Left) || comparesNullable(e.Right)
+	case *filter.Not:
+		return comparesNullable(e.Expr)
+	case *filter.Comparison:
+		return nullableFields[e.Field]
+	}
+	return false
+}
+
+// notEqual is the clause of "!=", which also holds for NULL in nullable
+// fields
+func notEqual(column string, e *filter.Comparison) string {
+	if nullableFields[e.Field] {
+		return "(" + column + " <> ? OR " + column + " IS NULL)"
+	}
+	return "(" + column + " <> ?)"
+}
+
+// textComparison compares text like the conditions do, in lowercase and
+// without accents
+func textComparison(column string, e *filter.Comparison) (string, []any) {
+	value := models.FoldText(e.Values[0].Text)
+	switch e.Operator {
+	case filter.OpNotEqual:
+		return notEqual(column, e), []any{value}
+	case filter.OpContains:
+		return "(" + column + ` LIKE ? ESCAPE '\')`, []any{"%" + likeEscaper.Replace(value) + "%"}
+	case filter.OpStartsWith:
+		return "(" + column + ` LIKE ? ESCAPE '\')`, []any{likeEscaper.Replace(value) + "%"}
+	case filter.OpEndsWith:
+		return "(" + column + ` LIKE ? ESCAPE '\')`, []any{"%" + likeEscaper.Replace(value)}
+	case filter.OpIn:
+		values := make([]string, 0, len(e.Values))
+		for _, v := range e.Values {
+			values = append(values, models.FoldText(v.Text))
+		}
+		return "(" + column + " IN ?)", []any{values}
+	}
+	return "(" + column + " = ?)", []any{value}
+}
+
+func numberComparison(column string, e *filter.Comparison) (string, []any) {
+	if e.Operator == filter.OpIn {
+		values := make([]int64, 0, len(e.Values))
+		for _, v := range e.Values {
+			values = append(values, v.Number)
+		}
+		return "(" + column + " IN ?)", []any{values}
+	}
+
+	if e.Operator == filter.OpNotEqual {
+		return notEqual(column, e), []any{e.Values[0].Number}
+	}
+	return "(" + column + " " + string(e.Operator) + " ?)", []any{e.Values[0].Number}
+}
+
+// timeComparison compares timestamps exactly, while a date stands for the
+// whole day: "> 2025-01-01" starts on January 2nd
+func timeComparison(column string, e *filter.Comparison) (string, []any) {
+	value := e.Values[0]
+	if !value.DateOnly {
+		op := string(e.Operator)
+		if e.Operator == filter.OpNotEqual {
+			op = "<>"
+		}
+		return "(" + column + " " + op + " ?)", []any{value.Time}
+	}
+
+	start, end := value.Time, value.Time.AddDate(0, 0, 1)
+	switch e.Operator {
+	case filter.OpNotEqual:
+		return "(" + column + " < ? OR " + column + " >= ?)", []any{start, end}
+	case filter.OpLess:
+		return "(" + column + " < ?)", []any{start}
+	case filter.OpLessEqual:
+		return "(" + column + " < ?)", []any{end}
+	case filter.OpGreater:
+		return "(" + column + " >= ?)", []any{end}
+	case filter.OpGreaterEqual:
+		return "(" + column + " >= ?)", []any{start}
+	}
+	return "(" + column + " >= ? AND " + column + " < ?)", []any{start, end}
+}
@@ -0,0 +1,167 @@
+package services
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"api-contacts-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// Facets SearchFacets can count
+const (
+	FacetCompany     = "company"
+	FacetEmailDomain = "email_domain"
+)
+
+var searchFacets = []string{FacetCompany, FacetEmailDomain}
+
+// DefaultFacetSize is how many buckets a facet returns, largest first
+const DefaultFacetSize = 10
+
+// FacetError is a facets parameter naming an unknown facet
+type FacetError struct {
+	message string
+}
+
+func (e *FacetError) Error() string {
+	return e.message
+}
+
+// ParseFacets reads a comma-separated list of facet names
+func ParseFacets(value string) ([]string, error) {
+	var facets []string
+	for _, name := range splitList(value) {
+		if !slices.Contains(searchFacets, name) {
+			return nil, &FacetError{fmt.Sprintf("unknown facet '%s', use any of: %s", name, strings.Join(searchFacets, ", "))}
+		}
+		if !slices.Contains(facets, name) {
+			facets = append(facets, name)
+		}
+	}
+	return facets, nil
+}
+
+// SearchFacets counts the contacts SearchContacts would match, over every
+// page, by company and by email domain. A contact with emails on several
+// domains counts once in each of them.
+func (s *ContactService) SearchFacets(opts SearchOptions, filter ContactFilter, facets []string, size int) (map[string][]models.FacetBucket, error) {
+	// Search indexes and the fuzzy fallback only know their matches once
+	// scored in Go, which are counted a batch at a time and added up
+	matches, err := s.scoredMatches(opts, filter)
+	if err != nil {
+		return nil, err
+	}
+	if matches != nil {
+		counts := make(map[string]map[string]*models.FacetBucket, len(facets))
+		for _, facet := range facets {
+			counts[facet] = map[string]*models.FacetBucket{}
+		}
+		err := s.eachMatch(matches, filter, func(batch []scoredMatch) error {
+			ids := make([]uint, len(batch))
+			for i, match := range batch {
+				ids[i] = match.id
+			}
+			for _, facet := range facets {
+				var rows []facetRow
+				if err := s.facetQuery(s.db, facet, ids).Scan(&rows).Error; err != nil {
+					return err
+				}
+				for _, row := range rows {
+					bucket, ok := counts[facet][row.Key]
+					if !ok {
+						counts[facet][row.Key] = &models.FacetBucket{Value: row.Value, Count: row.Count}
+						continue
+					}
+					bucket.Value = min(bucket.Value, row.Value)
+					bucket.Count += row.Count
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		result := make(map[string][]models.FacetBucket, len(facets))
+		for _, facet := range facets {
+			result[facet] = largestBuckets(counts[facet], size)
+		}
+		return result, nil
+	}
+
+	result := make(map[string][]models.FacetBucket, len(facets))
+	search := newSearch(s.db, opts)
+	err = search.session(s.db, func(tx *gorm.DB) error {
+		matching := s.searchQuery(tx, search, filter).Select("contacts.id")
+		for _, facet := range facets {
+			rows := []facetRow{}
+			if err := s.facetQuery(tx, facet, matching).Order("count DESC, value").Limit(size).Scan(&rows).Error; err != nil {
+				return err
+			}
+			buckets := make([]models.FacetBucket, len(rows))
+			for i, row := range rows {
+				buckets[i] = models.FacetBucket{Value: row.Value, Count: row.Count}
+			}
+			result[facet] = buckets
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// largestBuckets orders counts like the facet queries do, largest first,
+// keeping size of them
+func largestBuckets(counts map[string]*models.FacetBucket, size int) []models.FacetBucket {
+	buckets := make([]models.FacetBucket, 0, len(counts))
+	for _, bucket := range counts {
+		buckets = append(buckets, *bucket)
+	}
+	slices.SortFunc(buckets, func(a, b models.FacetBucket) int {
+		if c := cmp.Compare(b.Count, a.Count); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Value, b.Value)
+	})
+	return buckets[:min(size, len(buckets))]
+}
+
+// facetRow is a facet bucket along with the key grouping it
+type facetRow struct {
+	Key   string
+	Value string
+	Count int64
+}
+
+// facetQuery groups the matching contacts, given as ids or a subquery, by
+// the value of a facet. Companies group by their folded company_key, so
+// spellings differing in case or accents count together, and show the
+// smallest of their names in byte order, which both databases and Go agree on.
+func (s *ContactService) facetQuery(db *gorm.DB, facet string, matches any) *gorm.DB {
+	if facet == FacetEmailDomain {
+		domain := "LOWER(substr(email, instr(email, '@') + 1))"
+		if isPostgres(db) {
+			domain = "LOWER(split_part(email, '@', 2))"
+		}
+		return db.Table("contact_emails").
+			Select(domain+" AS key, "+domain+" AS value, COUNT(DISTINCT contact_id) AS count").
+			Where("contact_id IN (?)", matches).
+			Group(domain)
+	}
+
+	name := "contacts.company"
+	if isPostgres(db) {
+		name += ` COLLATE "C"`
+	}
+	return db.Model(&models.Contact{}).
+		Select("contacts.company_key AS key, MIN("+name+") AS value, COUNT(*) AS count").
+		Where("contacts.id IN (?)", matches).
+		Where("contacts.company_key <> ''").
+		Group("contacts.company_key")
+}
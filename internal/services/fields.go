@@ -0,0 +1,198 @@
+package services
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"api-contacts-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// IncludeRelationships embeds each contact's relationships
+const IncludeRelationships = "relationships"
+
+// contactIncludes are the related resources that can be embedded on request
+var contactIncludes = []string{IncludeRelationships}
+
+// contactColumns are the columns behind each scalar field of the response
+var contactColumns = map[string][]string{
+	"id":            {"id"},
+	"name":          {"name"},
+	"email":         {"email"},
+	"phone":         {"phone"},
+	"company":       {"company"},
+	"company_id":    {"company_id"},
+	"birthday":      {"birth_year", "birth_month", "birth_day"},
+	"custom_fields": {"custom_fields"},
+	"created_at":    {"created_at"},
+	"updated_at":    {"updated_at"},
+}
+
+// sortKeyColumns are the columns a sort field reads, loaded so that cursors
+// can be built from a sparse contact
+var sortKeyColumns = map[string]string{
+	"name":       "name_key",
+	"email":      "email",
+	"company":    "company_key",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+}
+
+// FieldSet is a sparse fieldset: the response fields to render, all of them
+// when Fields is empty, plus the related resources to embed
+type FieldSet struct {
+	Fields  []string
+	Include []string
+}
+
+// FieldSetError is a fields or include parameter naming something unknown
+type FieldSetError struct {
+	message string
+}
+
+func (e *FieldSetError) Error() string {
+	return e.message
+}
+
+// ParseFieldSet reads comma-separated fields and include lists, returning
+// nil when both are empty
+func ParseFieldSet(fields, include string) (*FieldSet, error) {
+	if fields == "" && include == "" {
+		return nil, nil
+	}
+
+	set := &FieldSet{}
+	for _, field := range splitList(fields) {
+		if !slices.Contains(models.ContactResponseFields, field) {
+			return nil, &FieldSetError{fmt.Sprintf("unknown field '%s', use any of: %s", field, strings.Join(models.ContactResponseFields, ", "))}
+		}
+		if !slices.Contains(set.Fields, field) {
+			set.Fields = append(set.Fields, field)
+		}
+	}
+	for _, name := range splitList(include) {
+		if !slices.Contains(contactIncludes, name) {
+			return nil, &FieldSetError{fmt.Sprintf("cannot include '%s', use any of: %s", name, strings.Join(contactIncludes, ", "))}
+		}
+		if !slices.Contains(set.Include, name) {
+			set.Include = append(set.Include, name)
+		}
+	}
+	return set, nil
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// wants reports whether a response field has to be loaded
+func (f *FieldSet) wants(field string) bool {
+	return f == nil || len(f.Fields) == 0 || slices.Contains(f.Fields, field)
+}
+
+func (f *FieldSet) includes(name string) bool {
+	return f != nil && slices.Contains(f.Include, name)
+}
+
+// Names lists the keys to render, or nil to render every field
+func (f *FieldSet) Names() []string {
+	if f == nil || len(f.Fields) == 0 {
+		return nil
+	}
+	return append(slices.Clone(f.Fields), f.Include...)
+}
+
+// columns lists the contact columns to select, or nil for all of them. The id
+// is always loaded for the associations, and so are the keys of the sort and
+// any extra columns the caller reads.
+func (f *FieldSet) columns(sort []SortField, extra ...string) []string {
+	if f == nil || len(f.Fields) == 0 {
+		return nil
+	}
+
+	columns := []string{"contacts.id"}
+	add := func(column string) {
+		if column = "contacts." + column; !slices.Contains(columns, column) {
+			columns = append(columns, column)
+		}
+	}
+	for _, field := range f.Fields {
+		for _, column := range contactColumns[field] {
+			add(column)
+		}
+	}
+	for _, field := range sort {
+		if column, ok := sortKeyColumns[field.Field]; ok {
+			add(column)
+		}
+	}
+	for _, column := range extra {
+		add(column)
+	}
+	return columns
+}
+
+// selectColumns restricts a contacts query to the columns of the fieldset
+func (f *FieldSet) selectColumns(query *gorm.DB, sort []SortField, extra ...string) *gorm.DB {
+	if columns := f.columns(sort, extra...); columns != nil {
+		return query.Select(columns)
+	}
+	return query
+}
+
+// preload loads the associations rendering the fields of the set
+func (f *FieldSet) preload(db *gorm.DB) *gorm.DB {
+	primaryFirst := func(db *gorm.DB) *gorm.DB {
+		return db.Order("is_primary DESC, id")
+	}
+
+	if f.wants("tags") {
+		db = db.Preload("Tags", func(db *gorm.DB) *gorm.DB {
+			return db.Order("tags.name")
+		})
+	}
+	if f.wants("lists") {
+		db = db.Preload("Lists", func(db *gorm.DB) *gorm.DB {
+			return db.Order("contact_lists.name")
+		})
+	}
+	if f.wants("dates") {
+		db = db.Preload("Dates", func(db *gorm.DB) *gorm.DB {
+			return db.Order("month, day, id")
+		})
+	}
+	if f.wants("emails") {
+		db = db.Preload("Emails", primaryFirst)
+	}
+	if f.wants("phones") {
+		db = db.Preload("Phones", primaryFirst)
+	}
+	if f.wants("addresses") {
+		db = db.Preload("Addresses", func(db *gorm.DB) *gorm.DB {
+			return db.Order("id")
+		})
+	}
+	return db
+}
+
+// load fills in the data kept outside the contact rows: note summaries and
+// included relationships
+func (f *FieldSet) load(db *gorm.DB, contacts []models.Contact) error {
+	if f.wants("note_count") || f.wants("pinned_note") {
+		if err := loadNoteSummaries(db, contacts); err != nil {
+			return err
+		}
+	}
+	if f.includes(IncludeRelationships) {
+		return loadRelationships(db, contacts)
+	}
+	return nil
+}
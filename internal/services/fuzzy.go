@@ -0,0 +1,13 @@
+package services
+
+import "strings"
+
+// fuzzyScore is the best word similarity of a folded query to the name,
+// company or email of a contact, the score newFuzzySearch ranks by
+func fuzzyScore(query, nameKey, companyKey, email string) float64 {
+	return max(
+		wordSimilarity(query, nameKey),
+		wordSimilarity(query, companyKey),
+		wordSimilarity(query, strings.ToLower(email)),
+	)
+}
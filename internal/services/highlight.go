@@ -0,0 +1,110 @@
+package services
+
+import (
+	"slices"
+	"strings"
+
+	"api-contacts-go/internal/models"
+)
+
+// highlightColumns are the columns highlights are computed from
+var highlightColumns = []string{"name", "email", "company"}
+
+// tokenMatches finds the tokens of a text that match is true for. match
+// receives the word folded.
+func tokenMatches(tokens []searchToken, match func(word string) bool) []models.MatchRange {
+	var matches []models.MatchRange
+	for _, token := range tokens {
+		if match(token.word) {
+			matches = append(matches, models.MatchRange{Start: token.start, End: token.end})
+		}
+	}
+	return matches
+}
+
+// mergeRanges sorts ranges and joins the overlapping or adjacent ones
+func mergeRanges(ranges []models.MatchRange) []models.MatchRange {
+	slices.SortFunc(ranges, func(a, b models.MatchRange) int {
+		return a.Start - b.Start
+	})
+	merged := ranges[:0]
+	for _, r := range ranges {
+		if n := len(merged); n > 0 && r.Start <= merged[n-1].End {
+			merged[n-1].End = max(merged[n-1].End, r.End)
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// highlighter finds the parts of a field a search matched. A nil highlighter
+// highlights nothing.
+type highlighter func(field, text string) []models.MatchRange
+
+// newHighlighter follows the matching rules of the search mode: the words of
+// the query, whole, in name, email and company for text searches, similar
+// words for fuzzy ones and sound-alike words of the name for phonetic ones.
+// Text searches split both sides with searchTokens, like the search itself,
+// so a word is only marked where it could have matched. The digits of phone
+// number queries only match phones, which are not highlighted.
+func newHighlighter(opts SearchOptions) highlighter {
+	switch opts.Mode {
+	case SearchModeFuzzy:
+		terms := strings.Fields(models.FoldText(opts.Query))
+		return func(field, text string) []models.MatchRange {
+			return tokenMatches(wordTokens([]rune(text)), func(word string) bool {
+				return slices.ContainsFunc(terms, func(term string) bool {
+					return wordSimilarity(term, word) >= opts.Threshold
+				})
+			})
+		}
+	case SearchModePhonetic:
+		codes := strings.Fields(models.PhoneticKey(opts.Query))
+		return func(field, text string) []models.MatchRange {
+			if field != "name" {
+				return nil
+			}
+			return tokenMatches(wordTokens([]rune(text)), func(word string) bool {
+				key := models.PhoneticKey(word)
+				return key != "" && slices.Contains(codes, key)
+			})
+		}
+	}
+
+	words := parseTextQuery(opts.Query).words()
+	return func(field, text string) []models.MatchRange {
+		return tokenMatches(searchTokens(text), func(word string) bool {
+			return words[word]
+		})
+	}
+}
+
+// highlightContacts sets the highlights of each contact of a search page
+func highlightContacts(opts SearchOptions, contacts []models.Contact) {
+	highlight := newHighlighter(opts)
+	for i := range contacts {
+		contact := &contacts[i]
+		contact.Highlights = []models.Highlight{}
+		if highlight == nil {
+			continue
+		}
+		fields := []struct{ name, text string }{
+			{"name", contact.Name},
+			{"email", contact.Email},
+			{"company", contact.Company},
+		}
+		for _, field := range fields {
+			if field.text == "" {
+				continue
+			}
+			if matches := highlight(field.name, field.text); len(matches) > 0 {
+				contact.Highlights = append(contact.Highlights, models.Highlight{
+					Field:   field.name,
+					Text:    field.text,
+					Matches: mergeRanges(matches),
+				})
+			}
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"api-contacts-go/internal/models"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// MaxImportRows caps the records of an imported file
+const MaxImportRows = 5000
+
+// ImportFields are the contact fields CSV columns can be mapped to, besides
+// custom fields written as cf.<name>
+var ImportFields = []string{"name", "email", "phone", "company", "birthday"}
+
+// importEncodings are the character sets imported files can use. Excel saves
+// "CSV" files in Windows-1252, a superset of Latin-1.
+var importEncodings = map[string]encoding.Encoding{
+	"utf-8":        unicode.UTF8,
+	"latin1":       charmap.ISO8859_1,
+	"iso-8859-1":   charmap.ISO8859_1,
+	"windows-1252": charmap.Windows1252,
+	"cp1252":       charmap.Windows1252,
+}
+
+// ImportOptions describes how to read an imported CSV file. Mapping maps
+// column headers to fields, or to "" to ignore a column; without it, columns
+// named after a field are imported and the others ignored.
+type ImportOptions struct {
+	Delimiter rune
+	Encoding  string
+	Mapping   map[string]string
+}
+
+// ImportRow is a record of an imported file: the line it starts on and the
+// values of its mapped fields, leaving out empty cells
+type ImportRow struct {
+	Line   int
+	Values map[string]string
+}
+
+// ImportError is an imported file or option that cannot be read at all, as
+// opposed to a row failing validation
+type ImportError struct {
+	message string
+}
+
+func (e *ImportError) Error() string {
+	return e.message
+}
+
+// ParseImportDelimiter reads a delimiter given as a single character or
+// "tab"
+func ParseImportDelimiter(value string) (rune, error) {
+	if value == "" {
+		return ',', nil
+	}
+	if value == "tab" || value == `\t` {
+		return '\t', nil
+	}
+	delimiter, size := utf8.DecodeRuneInString(value)
+	if size != len(value) || delimiter == utf8.RuneError || strings.ContainsRune("\"\r\n", delimiter) {
+		return 0, &ImportError{"delimiter must be a single character other than a quote or line break, or 'tab'"}
+	}
+	return delimiter, nil
+}
+
+// ReadImport decodes a CSV file whose first record holds the column headers,
+// returning its rows and the headers of the columns left out
+func ReadImport(r io.Reader, opts ImportOptions) ([]ImportRow, []string, error) {
+	name := strings.ToLower(opts.Encoding)
+	if name == "" {
+		name = "utf-8"
+	}
+	charset, ok := importEncodings[name]
+	if !ok {
+		names := make([]string, 0, len(importEncodings))
+		for known := range importEncodings {
+			names = append(names, known)
+		}
+		slices.Sort(names)
+		return nil, nil, &ImportError{fmt.Sprintf("unknown encoding '%s', use one of: %s", opts.Encoding, strings.Join(names, ", "))}
+	}
+
+	// Excel starts UTF-8 files with a byte order mark
+	buffered := bufio.NewReader(charset.NewDecoder().Reader(r))
+	if bom, err := buffered.Peek(3); err == nil && bytes.Equal(bom, []byte("\xef\xbb\xbf")) {
+		buffered.Discard(3)
+	}
+
+	reader := csv.NewReader(buffered)
+	reader.Comma = opts.Delimiter
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	headers, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil, &ImportError{"the file is empty"}
+	}
+	if err != nil {
+		return nil, nil, &ImportError{fmt.Sprintf("invalid CSV: %s", err)}
+	}
+	for i := range headers {
+		headers[i] = strings.TrimSpace(headers[i])
+	}
+
+	fields, ignored, err := mapImportColumns(headers, opts.Mapping)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var rows []ImportRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, &ImportError{fmt.Sprintf("invalid CSV: %s", err)}
+		}
+		if len(rows) == MaxImportRows {
+			return nil, nil, &ImportError{fmt.Sprintf("the file has more than %d rows", MaxImportRows)}
+		}
+
+		line, _ := reader.FieldPos(0)
+		row := ImportRow{Line: line, Values: map[string]string{}}
+		for i, value := range record {
+			if value = strings.TrimSpace(value); i < len(fields) && fields[i] != "" && value != "" {
+				row.Values[fields[i]] = value
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, ignored, nil
+}
+
+// mapImportColumns finds the field of each column, "" for ignored ones
+func mapImportColumns(headers []string, mapping map[string]string) ([]string, []string, error) {
+	for column := range mapping {
+		if !slices.Contains(headers, column) {
+			return nil, nil, &ImportError{fmt.Sprintf("mapped column '%s' is not in the file", column)}
+		}
+	}
+
+	fields := make([]string, len(headers))
+	var ignored []string
+	for i, header := range headers {
+		field := strings.ToLower(header)
+		if mapping != nil {
+			field = mapping[header]
+			if field != "" && !isImportField(field) {
+				return nil, nil, &ImportError{fmt.Sprintf("cannot map column '%s' to '%s', use one of: %s or cf.<name>", header, field, strings.Join(ImportFields, ", "))}
+			}
+		}
+		if field == "" || !isImportField(field) {
+			ignored = append(ignored, header)
+			continue
+		}
+		if slices.Contains(fields, field) {
+			return nil, nil, &ImportError{fmt.Sprintf("more than one column maps to '%s'", field)}
+		}
+		fields[i] = field
+	}
+
+	if !slices.ContainsFunc(fields, func(field string) bool { return field != "" }) {
+		return nil, nil, &ImportError{fmt.Sprintf("no column maps to a contact field, name them after %s or cf.<name>, or send a mapping", strings.Join(ImportFields, ", "))}
+	}
+	return fields, ignored, nil
+}
+
+func isImportField(field string) bool {
+	name, custom := strings.CutPrefix(field, "cf.")
+	return slices.Contains(ImportFields, field) || custom && name != ""
+}
+
+// FindContactByEmail returns the id of the live contact using an email,
+// as its primary address or any other, or 0 when there is none
+func (s *ContactService) FindContactByEmail(email string) (uint, error) {
+	var ids []uint
+	email = strings.ToLower(strings.TrimSpace(email))
+	err := s.db.Model(&models.Contact{}).
+		Where("LOWER(contacts.email) = ? OR contacts.id IN (SELECT contact_id FROM contact_emails WHERE LOWER(email) = ?)", email, email).
+		Order("contacts.id").
+		Limit(1).
+		Pluck("contacts.id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+	return ids[0], nil
+}
+
+// CheckNewContact reports the errors CreateContact would return for a
+// contact, without storing anything
+func (s *ContactService) CheckNewContact(contact *models.Contact) error {
+	check := *contact
+	if err := prepareContactMethods(&check); err != nil {
+		return err
+	}
+	if err := checkEmailsAvailable(s.db, 0, check.Emails); err != nil {
+		return err
+	}
+	if check.CompanyID != nil {
+		var count int64
+		if err := s.db.Model(&models.Company{}).Where("id = ?", *check.CompanyID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return ErrCompanyNotFound
+		}
+	}
+	return nil
+}
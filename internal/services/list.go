@@ -0,0 +1,199 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"api-contacts-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrListNotFound = errors.New("list not found")
+	ErrListExists   = errors.New("list already exists")
+)
+
+// MissingContactsError reports the requested members that are not live contacts
+type MissingContactsError struct {
+	IDs []uint
+}
+
+func (e *MissingContactsError) Error() string {
+	ids := make([]string, len(e.IDs))
+	for i, id := range e.IDs {
+		ids[i] = fmt.Sprint(id)
+	}
+	return "contacts not found: " + strings.Join(ids, ", ")
+}
+
+type ListService struct {
+	db *gorm.DB
+}
+
+func NewListService(db *gorm.DB) *ListService {
+	return &ListService{db: db}
+}
+
+func (s *ListService) GetLists(page, limit int) ([]models.ContactList, int64, error) {
+	lists := []models.ContactList{}
+	var total int64
+
+	if err := s.db.Model(&models.ContactList{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := s.db.Scopes(withMemberCount).Offset(offset).Limit(limit).Order("contact_lists.name").Find(&lists).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return lists, total, nil
+}
+
+func (s *ListService) GetList(id uint) (*models.ContactList, error) {
+	var list models.ContactList
+	if err := s.db.Scopes(withMemberCount).First(&list, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrListNotFound
+		}
+		return nil, err
+	}
+	return &list, nil
+}
+
+func (s *ListService) CreateList(req *models.CreateContactListRequest) (*models.ContactList, error) {
+	list := models.ContactList{
+		Name:        strings.TrimSpace(req.Name),
+		Description: req.Description,
+	}
+
+	if err := s.checkNameAvailable(0, list.Name); err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Create(&list).Error; err != nil {
+		return nil, err
+	}
+
+	return &list, nil
+}
+
+func (s *ListService) UpdateList(id uint, req *models.UpdateContactListRequest) (*models.ContactList, error) {
+	list, err := s.GetList(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		list.Name = strings.TrimSpace(*req.Name)
+		if err := s.checkNameAvailable(list.ID, list.Name); err != nil {
+			return nil, err
+		}
+	}
+	if req.Description != nil {
+		list.Description = *req.Description
+	}
+
+	if err := s.db.Save(list).Error; err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (s *ListService) DeleteList(id uint) error {
+	list, err := s.GetList(id)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table("contact_list_members").Where("contact_list_id = ?", list.ID).Delete(nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.ContactList{}, list.ID).Error
+	})
+}
+
+// AddMembers adds contacts to a list, ignoring the ones already in it
+func (s *ListService) AddMembers(id uint, contactIDs []uint) (*models.ContactList, error) {
+	list, err := s.GetList(id)
+	if err != nil {
+		return nil, err
+	}
+
+	contacts, err := s.findContacts(contactIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Model(list).Association("Members").Append(contacts); err != nil {
+		return nil, err
+	}
+
+	return s.GetList(id)
+}
+
+func (s *ListService) RemoveMembers(id uint, contactIDs []uint) (*models.ContactList, error) {
+	list, err := s.GetList(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Table("contact_list_members").
+		Where("contact_list_id = ? AND contact_id IN ?", list.ID, contactIDs).
+		Delete(nil).Error; err != nil {
+		return nil, err
+	}
+
+	return s.GetList(id)
+}
+
+// findContacts loads the given contacts, failing if any of them is missing
+func (s *ListService) findContacts(ids []uint) ([]models.Contact, error) {
+	var contacts []models.Contact
+	if err := s.db.Where("id IN ?", ids).Find(&contacts).Error; err != nil {
+		return nil, err
+	}
+
+	found := make(map[uint]bool, len(contacts))
+	for _, contact := range contacts {
+		found[contact.ID] = true
+	}
+
+	var missing []uint
+	for _, id := range ids {
+		if !found[id] {
+			missing = append(missing, id)
+			found[id] = true
+		}
+	}
+	if len(missing) > 0 {
+		return nil, &MissingContactsError{IDs: missing}
+	}
+
+	return contacts, nil
+}
+
+// checkNameAvailable enforces that list names are unique regardless of case,
+// the rule the LOWER(name) index of the migrations backs up
+func (s *ListService) checkNameAvailable(id uint, name string) error {
+	var count int64
+	if err := s.db.Model(&models.ContactList{}).
+		Where("LOWER(name) = ? AND id <> ?", strings.ToLower(name), id).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrListExists
+	}
+	return nil
+}
+
+// withMemberCount selects lists along with their number of live members
+func withMemberCount(db *gorm.DB) *gorm.DB {
+	return db.Select("contact_lists.*, (SELECT COUNT(*) FROM contact_list_members JOIN contacts ON contacts.id = contact_list_members.contact_id " +
+		"WHERE contact_list_members.contact_list_id = contact_lists.id AND contacts.deleted_at IS NULL) AS member_count")
+}
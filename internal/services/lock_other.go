@@ -0,0 +1,11 @@
+//go:build !unix
+
+package services
+
+import "os"
+
+// lockFile opens the file at path, creating it. Systems without flock take
+// no lock, leaving it to the deployment to run a single process per index.
+func lockFile(path string) (*os.File, error) {
+	return os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o644)
+}
@@ -0,0 +1,27 @@
+//go:build unix
+
+package services
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// lockFile opens the file at path, creating it, and takes an exclusive lock
+// on it, held until the file is closed or the process ends. It fails with
+// ErrSearchIndexLocked when another open file holds the lock.
+func lockFile(path string) (*os.File, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		file.Close()
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return nil, ErrSearchIndexLocked
+		}
+		return nil, err
+	}
+	return file, nil
+}
@@ -0,0 +1,192 @@
+package services
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"time"
+
+	"api-contacts-go/internal/models"
+
+	"golang.org/x/text/collate"
+)
+
+// scoredMatch holds a matching contact with the fields it can be sorted by
+type scoredMatch struct {
+	id         uint
+	score      float64
+	nameKey    string
+	email      string
+	companyKey string
+	createdAt  time.Time
+	updatedAt  time.Time
+}
+
+// compare orders two matches by one sort field, as the SQL columns would.
+// Text compares with collator, in the collation the sort columns use.
+func (m scoredMatch) compare(other scoredMatch, field string, collator *collate.Collator) int {
+	switch field {
+	case SortRelevance:
+		return cmp.Compare(m.score, other.score)
+	case "name":
+		return collated(collator, m.nameKey, other.nameKey)
+	case "email":
+		return collated(collator, m.email, other.email)
+	case "company":
+		return collated(collator, m.companyKey, other.companyKey)
+	case "created_at":
+		return m.createdAt.Compare(other.createdAt)
+	case "updated_at":
+		return m.updatedAt.Compare(other.updatedAt)
+	}
+	return cmp.Compare(m.id, other.id)
+}
+
+// matchRowBatch caps the ids bound to a single query
+const matchRowBatch = 1000
+
+// matchList holds the index hits of a search, in the order of the filter's
+// sort. The hits still have to go through the filter.
+type matchList struct {
+	matches []scoredMatch
+}
+
+// scoredMatches finds the matches of searches answered by a search index
+// other than SQLIndex, which ranks them. It returns nil for every search the
+// contacts query runs by itself.
+func (s *ContactService) scoredMatches(opts SearchOptions, filter ContactFilter) (*matchList, error) {
+	if strings.TrimSpace(opts.Query) == "" {
+		return nil, nil
+	}
+
+	hits, err := s.index.Match(opts, filter.sortOr(DefaultSearchSort))
+	if err != nil || hits == nil {
+		return nil, err
+	}
+	matches := make([]scoredMatch, len(hits))
+	for i, hit := range hits {
+		matches[i] = scoredMatch{id: hit.ID, score: hit.Score}
+	}
+	return &matchList{matches: matches}, nil
+}
+
+// eachMatch passes the matches allowed by the filter to fn in order, a batch at a
+// time. Unless the filter allows every contact, each batch of index hits is
+// checked with a query bound to the ids of the batch.
+func (s *ContactService) eachMatch(list *matchList, filter ContactFilter, fn func(matches []scoredMatch) error) error {
+	check := filter.narrows()
+	for batch := range slices.Chunk(list.matches, matchRowBatch) {
+		if check {
+			ids := make([]uint, len(batch))
+			for i, match := range batch {
+				ids[i] = match.id
+			}
+			var allowed []uint
+			err := s.applyFilter(s.db.Model(&models.Contact{}), filter).
+				Where("contacts.id IN ?", ids).
+				Pluck("contacts.id", &allowed).Error
+			if err != nil {
+				return err
+			}
+			kept := make(map[uint]bool, len(allowed))
+			for _, id := range allowed {
+				kept[id] = true
+			}
+			batch = slices.DeleteFunc(slices.Clone(batch), func(match scoredMatch) bool { return !kept[match.id] })
+		}
+		if err := fn(batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// collated compares two strings with collator, breaking its ties byte by
+// byte as the deterministic collations of PostgreSQL do
+func collated(collator *collate.Collator, a, b string) int {
+	if c := collator.CompareString(a, b); c != 0 {
+		return c
+	}
+	return strings.Compare(a, b)
+}
+
+// sortMatches gives matches the order applySort gives, ending with the id
+// tie-break
+func sortMatches(matches []scoredMatch, fields []SortField) {
+	// A collator is not safe for concurrent use, each sort gets its own
+	collator := newSortCollator()
+	slices.SortFunc(matches, func(a, b scoredMatch) int {
+		desc := false
+		for _, field := range fields {
+			c := a.compare(b, field.Field, collator)
+			if field.Desc {
+				c = -c
+			}
+			if c != 0 {
+				return c
+			}
+			desc = field.Desc
+		}
+		if desc {
+			return -a.compare(b, "id", collator)
+		}
+		return a.compare(b, "id", collator)
+	})
+}
+
+// pageMatches loads a page of the matches allowed by the filter, with the
+// score of each one in Contact.Score, returning how many there are in all
+func (s *ContactService) pageMatches(list *matchList, opts SearchOptions, page, limit int, filter ContactFilter) ([]models.Contact, int64, error) {
+	offset := (page - 1) * limit
+	var total int
+	var matches []scoredMatch
+	err := s.eachMatch(list, filter, func(batch []scoredMatch) error {
+		from := min(max(offset-total, 0), len(batch))
+		to := min(max(offset+limit-total, 0), len(batch))
+		matches = append(matches, batch[from:to]...)
+		total += len(batch)
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(matches) == 0 {
+		return []models.Contact{}, int64(total), nil
+	}
+
+	ids := make([]uint, len(matches))
+	for i, match := range matches {
+		ids[i] = match.id
+	}
+
+	var found []models.Contact
+	sparse := filter.Fields.selectColumns(s.db.Model(&models.Contact{}), nil, opts.columns()...)
+	if err := sparse.Scopes(filter.Fields.preload).Where("id IN ?", ids).Find(&found).Error; err != nil {
+		return nil, 0, err
+	}
+
+	byID := make(map[uint]models.Contact, len(found))
+	for _, contact := range found {
+		byID[contact.ID] = contact
+	}
+
+	contacts := make([]models.Contact, 0, len(matches))
+	for _, match := range matches {
+		contact, ok := byID[match.id]
+		if !ok {
+			continue
+		}
+		score := match.score
+		contact.Score = &score
+		contacts = append(contacts, contact)
+	}
+
+	if err := filter.Fields.load(s.db, contacts); err != nil {
+		return nil, 0, err
+	}
+	if opts.Highlight {
+		highlightContacts(opts, contacts)
+	}
+
+	return contacts, int64(total), nil
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"api-contacts-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+var ErrNoteNotFound = errors.New("note not found")
+
+type NoteService struct {
+	db *gorm.DB
+}
+
+func NewNoteService(db *gorm.DB) *NoteService {
+	return &NoteService{db: db}
+}
+
+// GetNotes lists a contact's notes with pinned ones first, newest first
+func (s *NoteService) GetNotes(contactID uint, page, limit int) ([]models.Note, int64, error) {
+	var contact models.Contact
+	if err := s.db.First(&contact, contactID).Error; err != nil {
+		return nil, 0, err
+	}
+
+	notes := []models.Note{}
+	var total int64
+
+	query := s.db.Model(&models.Note{}).Where("contact_id = ?", contactID).Session(&gorm.Session{})
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Offset(offset).Limit(limit).Order("pinned DESC, created_at DESC, id DESC").Find(&notes).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return notes, total, nil
+}
+
+func (s *NoteService) CreateNote(contactID uint, req *models.CreateNoteRequest) (*models.Note, error) {
+	var contact models.Contact
+	if err := s.db.First(&contact, contactID).Error; err != nil {
+		return nil, err
+	}
+
+	note := models.Note{
+		ContactID: contactID,
+		Body:      req.Body,
+		Author:    req.Author,
+		Pinned:    req.Pinned,
+	}
+
+	if err := s.db.Create(&note).Error; err != nil {
+		return nil, err
+	}
+
+	return &note, nil
+}
+
+func (s *NoteService) UpdateNote(contactID, noteID uint, req *models.UpdateNoteRequest) (*models.Note, error) {
+	note, err := s.findNote(contactID, noteID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Pinning is not an edit, only body changes are tracked
+	if req.Body != nil && *req.Body != note.Body {
+		now := time.Now()
+		note.Body = *req.Body
+		note.EditedAt = &now
+	}
+	if req.Pinned != nil {
+		note.Pinned = *req.Pinned
+	}
+
+	if err := s.db.Save(note).Error; err != nil {
+		return nil, err
+	}
+
+	return note, nil
+}
+
+func (s *NoteService) DeleteNote(contactID, noteID uint) error {
+	note, err := s.findNote(contactID, noteID)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Delete(note).Error
+}
+
+// findNote loads a note making sure it belongs to the given contact
+func (s *NoteService) findNote(contactID, noteID uint) (*models.Note, error) {
+	var contact models.Contact
+	if err := s.db.First(&contact, contactID).Error; err != nil {
+		return nil, err
+	}
+
+	var note models.Note
+	if err := s.db.Where("contact_id = ?", contactID).First(&note, noteID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrNoteNotFound
+		}
+		return nil, err
+	}
+
+	return &note, nil
+}
+
+// loadNoteSummaries fills the note count and latest pinned note of each contact
+func loadNoteSummaries(db *gorm.DB, contacts []models.Contact) error {
+	if len(contacts) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, 0, len(contacts))
+	for _, contact := range contacts {
+		ids = append(ids, contact.ID)
+	}
+
+	var counts []struct {
+		ContactID uint
+		Count     int64
+	}
+	if err := db.Model(&models.Note{}).
+		Select("contact_id, COUNT(*) AS count").
+		Where("contact_id IN ?", ids).
+		Group("contact_id").
+		Scan(&counts).Error; err != nil {
+		return err
+	}
+
+	var pinned []models.Note
+	if err := db.Where("contact_id IN ? AND pinned = ?", ids, true).
+		Order("contact_id, created_at DESC, id DESC").
+		Find(&pinned).Error; err != nil {
+		return err
+	}
+
+	countByContact := make(map[uint]int64, len(counts))
+	for _, count := range counts {
+		countByContact[count.ContactID] = count.Count
+	}
+
+	latestPinned := make(map[uint]*models.Note, len(pinned))
+	for i := range pinned {
+		if _, ok := latestPinned[pinned[i].ContactID]; !ok {
+			latestPinned[pinned[i].ContactID] = &pinned[i]
+		}
+	}
+
+	for i := range contacts {
+		contacts[i].NoteCount = countByContact[contacts[i].ID]
+		contacts[i].PinnedNote = latestPinned[contacts[i].ID]
+	}
+
+	return nil
+}
@@ -0,0 +1,241 @@
+package services
+
+import (
+	"errors"
+
+	"api-contacts-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrRelationshipNotFound   = errors.New("relationship not found")
+	ErrRelationshipExists     = errors.New("relationship already exists")
+	ErrRelatedContactNotFound = errors.New("related contact not found")
+	ErrSelfRelationship       = errors.New("a contact cannot be related to itself")
+	ErrRelationshipCycle      = errors.New("relationship would create a management cycle")
+)
+
+type RelationshipService struct {
+	db *gorm.DB
+}
+
+func NewRelationshipService(db *gorm.DB) *RelationshipService {
+	return &RelationshipService{db: db}
+}
+
+func (s *RelationshipService) GetRelationships(contactID uint) ([]models.Relationship, error) {
+	var contact models.Contact
+	if err := s.db.First(&contact, contactID).Error; err != nil {
+		return nil, err
+	}
+
+	relationships := []models.Relationship{}
+	if err := s.db.Preload("RelatedContact").
+		Where("contact_id = ?", contactID).
+		Order("type, id").
+		Find(&relationships).Error; err != nil {
+		return nil, err
+	}
+
+	return relationships, nil
+}
+
+// CreateRelationship links two contacts, also storing the inverse link when
+// the relationship is bidirectional
+func (s *RelationshipService) CreateRelationship(contactID uint, req *models.CreateRelationshipRequest) (*models.Relationship, error) {
+	if contactID == req.RelatedContactID {
+		return nil, ErrSelfRelationship
+	}
+
+	relationship := models.Relationship{
+		ContactID:        contactID,
+		RelatedContactID: req.RelatedContactID,
+		Type:             req.Type,
+	}
+	if req.Type == models.RelationshipCustom {
+		relationship.Label = req.Label
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var contact models.Contact
+		if err := tx.First(&contact, contactID).Error; err != nil {
+			return err
+		}
+
+		var related models.Contact
+		if err := tx.First(&related, req.RelatedContactID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return ErrRelatedContactNotFound
+			}
+			return err
+		}
+
+		if err := checkRelationshipAvailable(tx, relationship); err != nil {
+			return err
+		}
+
+		if boss, subordinate, ok := managementEdge(relationship); ok {
+			cycle, err := manages(tx, subordinate, boss)
+			if err != nil {
+				return err
+			}
+			if cycle {
+				return ErrRelationshipCycle
+			}
+		}
+
+		if err := tx.Create(&relationship).Error; err != nil {
+			return err
+		}
+
+		if req.Bidirectional {
+			inverse := models.Relationship{
+				ContactID:        relationship.RelatedContactID,
+				RelatedContactID: relationship.ContactID,
+				Type:             models.RelationshipInverses[relationship.Type],
+				Label:            relationship.Label,
+			}
+			if err := checkRelationshipAvailable(tx, inverse); err != nil {
+				return err
+			}
+			return tx.Create(&inverse).Error
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Preload("RelatedContact").First(&relationship, relationship.ID).Error; err != nil {
+		return nil, err
+	}
+	return &relationship, nil
+}
+
+// DeleteRelationship removes a relationship along with its inverse, if any
+func (s *RelationshipService) DeleteRelationship(contactID, relationshipID uint) error {
+	var contact models.Contact
+	if err := s.db.First(&contact, contactID).Error; err != nil {
+		return err
+	}
+
+	var relationship models.Relationship
+	if err := s.db.Where("contact_id = ?", contactID).First(&relationship, relationshipID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return ErrRelationshipNotFound
+		}
+		return err
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("contact_id = ? AND related_contact_id = ? AND type = ? AND label = ?",
+			relationship.RelatedContactID, relationship.ContactID,
+			models.RelationshipInverses[relationship.Type], relationship.Label,
+		).Delete(&models.Relationship{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&relationship).Error
+	})
+}
+
+// deleteContactRelationships removes every relationship a contact takes part in
+func deleteContactRelationships(tx *gorm.DB, contactID uint) error {
+	return tx.Where("contact_id = ? OR related_contact_id = ?", contactID, contactID).
+		Delete(&models.Relationship{}).Error
+}
+
+func checkRelationshipAvailable(tx *gorm.DB, relationship models.Relationship) error {
+	var count int64
+	if err := tx.Model(&models.Relationship{}).
+		Where("contact_id = ? AND related_contact_id = ? AND type = ? AND label = ?",
+			relationship.ContactID, relationship.RelatedContactID, relationship.Type, relationship.Label).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrRelationshipExists
+	}
+	return nil
+}
+
+// managementEdge reads a hierarchical relationship as a boss/subordinate pair
+func managementEdge(relationship models.Relationship) (boss, subordinate uint, ok bool) {
+	switch relationship.Type {
+	case models.RelationshipManager:
+		return relationship.RelatedContactID, relationship.ContactID, true
+	case models.RelationshipReport:
+		return relationship.ContactID, relationship.RelatedContactID, true
+	}
+	return 0, 0, false
+}
+
+// manages reports whether boss is above subordinate in the management chain,
+// walking down the hierarchy one level per query
+func manages(tx *gorm.DB, boss, subordinate uint) (bool, error) {
+	visited := map[uint]bool{boss: true}
+	level := []uint{boss}
+
+	for len(level) > 0 {
+		var below []uint
+		if err := tx.Model(&models.Relationship{}).
+			Where("type = ? AND related_contact_id IN ?", models.RelationshipManager, level).
+			Pluck("contact_id", &below).Error; err != nil {
+			return false, err
+		}
+
+		var reports []uint
+		if err := tx.Model(&models.Relationship{}).
+			Where("type = ? AND contact_id IN ?", models.RelationshipReport, level).
+			Pluck("related_contact_id", &reports).Error; err != nil {
+			return false, err
+		}
+
+		level = level[:0]
+		for _, id := range append(below, reports...) {
+			if id == subordinate {
+				return true, nil
+			}
+			if !visited[id] {
+				visited[id] = true
+				level = append(level, id)
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// loadRelationships fills in the relationships of each contact, every one
+// getting at least an empty list
+func loadRelationships(db *gorm.DB, contacts []models.Contact) error {
+	if len(contacts) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, 0, len(contacts))
+	for _, contact := range contacts {
+		ids = append(ids, contact.ID)
+	}
+
+	var relationships []models.Relationship
+	if err := db.Preload("RelatedContact").
+		Where("contact_id IN ?", ids).
+		Order("type, id").
+		Find(&relationships).Error; err != nil {
+		return err
+	}
+
+	byContact := make(map[uint][]models.Relationship, len(contacts))
+	for _, relationship := range relationships {
+		byContact[relationship.ContactID] = append(byContact[relationship.ContactID], relationship)
+	}
+	for i := range contacts {
+		contacts[i].Relationships = byContact[contacts[i].ID]
+		if contacts[i].Relationships == nil {
+			contacts[i].Relationships = []models.Relationship{}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+package services
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"api-contacts-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// Search modes accepted by SearchOptions.Mode
+const (
+	SearchModeText     = "text"
+	SearchModeFuzzy    = "fuzzy"
+	SearchModePhonetic = "phonetic"
+)
+
+// DefaultFuzzyThreshold is the minimum similarity of a fuzzy match
+const DefaultFuzzyThreshold = 0.3
+
+// SearchOptions describes the text part of a contact search
+type SearchOptions struct {
+	Query     string
+	Mode      string
+	Threshold float64
+	// Highlight locates the matched parts of name, email and company
+	Highlight bool
+}
+
+// columns are the contact columns the search reads besides the fieldset
+func (o SearchOptions) columns() []string {
+	if o.Highlight {
+		return highlightColumns
+	}
+	return nil
+}
+
+// textSearch is the text part of a contact search: the condition a contact
+// must meet and the expression ranking the contacts that do, plus a session
+// setting some conditions depend on
+type textSearch struct {
+	condition     string
+	conditionArgs []any
+	score         string
+	scoreArgs     []any
+	setting       string
+	settingValue  string
+}
+
+// newSearch builds the text search for the requested mode, returning nil for
+// an empty query. Text queries that look like a phone number also match
+// phones by their digits.
+func newSearch(db *gorm.DB, opts SearchOptions) *textSearch {
+	switch opts.Mode {
+	case SearchModeFuzzy:
+		return newFuzzySearch(opts)
+	case SearchModePhonetic:
+		return newPhoneticSearch(db, opts.Query)
+	}
+	if isPhoneQuery(opts.Query) {
+		return newPhoneSearch(opts.Query)
+	}
+	return newTextSearch(opts.Query)
+}
+
+// newFuzzySearch matches name, company and email by trigram word similarity
+// in PostgreSQL. The %> operators can use the trigram indexes and compare
+// against pg_trgm.word_similarity_threshold, set for the search session.
+// PostgreSQL is the only database it runs on.
+func newFuzzySearch(opts SearchOptions) *textSearch {
+	query := strings.TrimSpace(models.FoldText(opts.Query))
+	if query == "" {
+		return nil
+	}
+
+	return &textSearch{
+		condition:     "contacts.name_key %> ? OR contacts.company_key %> ? OR LOWER(contacts.email) %> ?",
+		conditionArgs: []any{query, query, query},
+		score: "GREATEST(word_similarity(?, contacts.name_key), word_similarity(?, contacts.company_key), " +
+			"word_similarity(?, LOWER(contacts.email)))",
+		scoreArgs:    []any{query, query, query},
+		setting:      "pg_trgm.word_similarity_threshold",
+		settingValue: strconv.FormatFloat(opts.Threshold, 'f', -1, 64),
+	}
+}
+
+// minPhoneDigits is how many digits a query needs to be taken as a phone number
+const minPhoneDigits = 4
+
+// isPhoneQuery reports whether a query is made only of digits and the
+// characters used to format phone numbers
+func isPhoneQuery(query string) bool {
+	digits := 0
+	for _, r := range query {
+		switch {
+		case r >= '0' && r <= '9':
+			digits++
+		case strings.ContainsRune(" +-().", r):
+		default:
+			return false
+		}
+	}
+	return digits >= minPhoneDigits
+}
+
+// newPhoneSearch matches the words of the query like any text search, and
+// also contacts with a phone number containing its digits, whatever the
+// formatting of either side. Phone matches add to the score, a whole number
+// more than part of one. Each side runs as its own indexed query, joined
+// with UNION, since an OR between them would leave both indexes unused.
+func newPhoneSearch(query string) *textSearch {
+	text := newTextSearch(query)
+	digits := models.PhoneDigits(query)
+
+	return &textSearch{
+		// The inner contacts shadows the outer one in the text condition
+		condition: "contacts.id IN (SELECT id FROM contacts WHERE " + text.condition +
+			" UNION SELECT contact_id FROM contact_phones WHERE digits LIKE ?)",
+		conditionArgs: append(slices.Clone(text.conditionArgs), "%"+digits+"%"),
+		score: text.score + " + CASE WHEN contacts.id IN (SELECT contact_id FROM contact_phones WHERE digits = ?) THEN 1.0 " +
+			"WHEN contacts.id IN (SELECT contact_id FROM contact_phones WHERE digits LIKE ?) THEN 0.5 ELSE 0 END",
+		scoreArgs: append(slices.Clone(text.scoreArgs), digits, "%"+digits+"%"),
+	}
+}
+
+// newPhoneticSearch matches contacts whose name has a word sounding like each
+// word of the query, comparing the stored models.PhoneticKey codes. PostgreSQL
+// looks the codes up in phonetic_vector through its GIN index. Names spelled
+// exactly as queried rank first.
+func newPhoneticSearch(db *gorm.DB, query string) *textSearch {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil
+	}
+
+	codes := strings.Fields(models.PhoneticKey(query))
+	if len(codes) == 0 {
+		// Queries without letters have no sound to match
+		return &textSearch{condition: "1 = 0", score: "0"}
+	}
+
+	search := &textSearch{
+		score:     `CASE WHEN contacts.name_key LIKE ? ESCAPE '\' THEN 1.0 ELSE 0.5 END`,
+		scoreArgs: []any{"%" + likeEscaper.Replace(models.FoldText(query)) + "%"},
+	}
+	if isPostgres(db) {
+		// Codes are plain lowercase letters, which plainto_tsquery requires all of
+		search.condition = "contacts.phonetic_vector @@ plainto_tsquery('simple', ?)"
+		search.conditionArgs = []any{strings.Join(codes, " ")}
+		return search
+	}
+
+	conditions := make([]string, 0, len(codes))
+	for _, code := range codes {
+		conditions = append(conditions, "(' ' || contacts.phonetic_key || ' ') LIKE ?")
+		search.conditionArgs = append(search.conditionArgs, "% "+code+" %")
+	}
+	search.condition = strings.Join(conditions, " AND ")
+
+	return search
+}
+
+// newTextSearch matches the maintained search_vector column with
+// websearch_to_tsquery and ranks with ts_rank, both ignoring accents through
+// f_unaccent, returning nil for an empty query. The vector also holds the
+// secondary emails and phone digits, so the GIN index answers the whole
+// condition. The parser keeps dotted words such as domains whole, while the
+// vector holds emails both whole and split into parts, so the query is also
+// tried with its dots as spaces. PostgreSQL is the only database it runs on.
+func newTextSearch(query string) *textSearch {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil
+	}
+
+	whole := "websearch_to_tsquery('simple', f_unaccent(?))"
+	split := "websearch_to_tsquery('simple', translate(f_unaccent(?), '.', ' '))"
+	return &textSearch{
+		condition:     "(contacts.search_vector @@ " + whole + " OR contacts.search_vector @@ " + split + ")",
+		conditionArgs: []any{query, query},
+		score:         "GREATEST(ts_rank(contacts.search_vector, " + whole + "), ts_rank(contacts.search_vector, " + split + "))",
+		scoreArgs:     []any{query, query},
+	}
+}
+
+// apply restricts a contacts query to the matching contacts
+func (t *textSearch) apply(query *gorm.DB) *gorm.DB {
+	if t == nil {
+		return query
+	}
+	return query.Where(t.condition, t.conditionArgs...)
+}
+
+// rank selects the given columns, or all of them when nil, and the score of
+// each contact, which sorting by relevance uses
+func (t *textSearch) rank(query *gorm.DB, columns []string) *gorm.DB {
+	selected := "contacts.*"
+	if columns != nil {
+		selected = strings.Join(columns, ", ")
+	}
+	if t == nil {
+		return query.Select(selected)
+	}
+	return query.Select(selected+", "+t.score+" AS score", t.scoreArgs...)
+}
+
+// session runs fn on a connection configured for the search, inside a
+// transaction when a setting has to be applied
+func (t *textSearch) session(db *gorm.DB, fn func(tx *gorm.DB) error) error {
+	if t == nil || t.setting == "" {
+		return fn(db)
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT set_config(?, ?, true)", t.setting, t.settingValue).Error; err != nil {
+			return err
+		}
+		return fn(tx)
+	})
+}
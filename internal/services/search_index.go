@@ -0,0 +1,214 @@
+package services
+
+import (
+	"fmt"
+	"slices"
+
+	"api-contacts-go/internal/models"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Search backends accepted by NewSearchIndex
+const (
+	SearchBackendSQL   = "sql"
+	SearchBackendIndex = "index"
+)
+
+// SearchIndex answers the text part of contact searches. SQLIndex, the
+// default, searches the contacts table itself. Other indexes keep their own
+// copy of the searchable text, which the services refresh on every change to
+// a contact, so that searches only read a page of matches from the database.
+type SearchIndex interface {
+	// Match finds the contacts matching a search, in the order of sort. A nil
+	// result leaves the matching to the contacts query.
+	Match(opts SearchOptions, sort []SortField) ([]SearchHit, error)
+	// Index adds or refreshes the entries of contacts
+	Index(contacts ...models.Contact) error
+	// Remove drops the entries of contacts
+	Remove(ids ...uint) error
+	// Rebuild replaces every entry with the contacts load passes to add, one
+	// batch at a time
+	Rebuild(load func(add func(contacts []models.Contact)) error) error
+}
+
+// SearchHit is a contact matched by a SearchIndex, with its relevance
+type SearchHit struct {
+	ID    uint
+	Score float64
+}
+
+// SQLIndex runs searches in the database, on the columns and indexes the
+// migrations maintain, so it has nothing to keep in sync
+type SQLIndex struct{}
+
+func (SQLIndex) Match(opts SearchOptions, sort []SortField) ([]SearchHit, error) { return nil, nil }
+func (SQLIndex) Index(contacts ...models.Contact) error                          { return nil }
+func (SQLIndex) Remove(ids ...uint) error                                        { return nil }
+func (SQLIndex) Rebuild(load func(add func(contacts []models.Contact)) error) error {
+	return nil
+}
+
+// NewSearchIndex opens the index of a search backend: SQLIndex for "sql" or
+// an empty backend, a DiskIndex stored at path for "index"
+func NewSearchIndex(backend, path string) (SearchIndex, error) {
+	switch backend {
+	case "", SearchBackendSQL:
+		return SQLIndex{}, nil
+	case SearchBackendIndex:
+		index, err := OpenDiskIndex(path)
+		if err != nil {
+			return nil, err
+		}
+		return index, nil
+	}
+	return nil, fmt.Errorf("unknown search backend '%s', use '%s' or '%s'", backend, SearchBackendSQL, SearchBackendIndex)
+}
+
+// queueIndexUpdates marks contacts for the index in the transaction changing
+// them, so that the change reaches the index even when the process stops
+// before syncSearchIndex runs
+func queueIndexUpdates(tx *gorm.DB, index SearchIndex, ids ...uint) error {
+	if _, ok := index.(SQLIndex); ok || len(ids) == 0 {
+		return nil
+	}
+
+	// A contact can only appear once in an upsert
+	ids = slices.Compact(slices.Sorted(slices.Values(ids)))
+	updates := make([]models.SearchIndexUpdate, len(ids))
+	for i, id := range ids {
+		updates[i] = models.SearchIndexUpdate{ContactID: id, Revision: 1}
+	}
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "contact_id"}},
+		DoUpdates: clause.Assignments(map[string]any{"revision": gorm.Expr("search_index_updates.revision + 1")}),
+	}).CreateInBatches(updates, matchRowBatch).Error
+}
+
+// syncSearchIndex brings the index entries of the marked contacts among ids
+// up to date, dropping the ones deleted since
+func syncSearchIndex(db *gorm.DB, index SearchIndex, ids ...uint) error {
+	if _, ok := index.(SQLIndex); ok {
+		return nil
+	}
+
+	for batch := range slices.Chunk(ids, matchRowBatch) {
+		var updates []models.SearchIndexUpdate
+		if err := db.Where("contact_id IN ?", batch).Find(&updates).Error; err != nil {
+			return err
+		}
+		if err := applyIndexUpdates(db, index, updates); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// refreshSearchIndex runs syncSearchIndex once a change is committed. The
+// change is saved by then, so a failure is only logged, leaving the contacts
+// marked for SyncSearchIndex to retry.
+func refreshSearchIndex(db *gorm.DB, index SearchIndex, ids ...uint) {
+	if err := syncSearchIndex(db, index, ids...); err != nil {
+		logrus.Warnf("Failed to update the search index of %d contacts, it will be retried: %v", len(ids), err)
+	}
+}
+
+// applyIndexUpdates indexes the current state of marked contacts, then
+// clears the marks unless the contact changed again in the meantime
+func applyIndexUpdates(db *gorm.DB, index SearchIndex, updates []models.SearchIndexUpdate) error {
+	if len(updates) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(updates))
+	for i, update := range updates {
+		ids[i] = update.ContactID
+	}
+	var contacts []models.Contact
+	if err := db.Where("id IN ?", ids).Find(&contacts).Error; err != nil {
+		return err
+	}
+
+	live := make(map[uint]bool, len(contacts))
+	for _, contact := range contacts {
+		live[contact.ID] = true
+	}
+	var removed []uint
+	for _, id := range ids {
+		if !live[id] {
+			removed = append(removed, id)
+		}
+	}
+
+	if err := index.Index(contacts...); err != nil {
+		return err
+	}
+	if err := index.Remove(removed...); err != nil {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, update := range updates {
+			err := tx.Where("contact_id = ? AND revision = ?", update.ContactID, update.Revision).
+				Delete(&models.SearchIndexUpdate{}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// SyncSearchIndex applies the index updates still marked, left by failed
+// updates or by a process stopped before indexing its changes, returning
+// how many contacts were refreshed
+func (s *ContactService) SyncSearchIndex() (int, error) {
+	if _, ok := s.index.(SQLIndex); ok {
+		return 0, nil
+	}
+
+	synced := 0
+	var last uint
+	for {
+		var updates []models.SearchIndexUpdate
+		if err := s.db.Where("contact_id > ?", last).Order("contact_id").Limit(reindexBatch).Find(&updates).Error; err != nil {
+			return synced, err
+		}
+		if len(updates) == 0 {
+			return synced, nil
+		}
+		if err := applyIndexUpdates(s.db, s.index, updates); err != nil {
+			return synced, err
+		}
+		synced += len(updates)
+		last = updates[len(updates)-1].ContactID
+	}
+}
+
+// reindexBatch is how many contacts Reindex reads at a time
+const reindexBatch = 500
+
+// Reindex rebuilds the search index from every live contact, feeding it one
+// batch at a time, returning how many were indexed. It can run while the
+// index serves searches and changes. Contacts changed during the rebuild are
+// still marked afterwards, and refreshed again.
+func (s *ContactService) Reindex() (int, error) {
+	indexed := 0
+	err := s.index.Rebuild(func(add func(contacts []models.Contact)) error {
+		var batch []models.Contact
+		return s.db.FindInBatches(&batch, reindexBatch, func(tx *gorm.DB, _ int) error {
+			add(batch)
+			indexed += len(batch)
+			return nil
+		}).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	if _, err := s.SyncSearchIndex(); err != nil {
+		return 0, err
+	}
+	return indexed, nil
+}
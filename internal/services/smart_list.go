@@ -0,0 +1,260 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"api-contacts-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrSmartListNotFound = errors.New("smart list not found")
+	ErrSmartListExists   = errors.New("smart list already exists")
+)
+
+// SmartListError lists every problem found in a smart list definition
+type SmartListError struct {
+	Problems []string
+}
+
+func (e *SmartListError) Error() string {
+	return strings.Join(e.Problems, "; ")
+}
+
+type SmartListService struct {
+	db           *gorm.DB
+	contacts     *ContactService
+	customFields *CustomFieldService
+}
+
+// NewSmartListService creates a service evaluating smart lists with the
+// searches of index, or of the database alone when index is nil
+func NewSmartListService(db *gorm.DB, index SearchIndex) *SmartListService {
+	return &SmartListService{
+		db:           db,
+		contacts:     NewContactService(db, index),
+		customFields: NewCustomFieldService(db),
+	}
+}
+
+// GetSmartLists returns a page of smart lists, evaluating each one to count
+// its members only when counts is set
+func (s *SmartListService) GetSmartLists(page, limit int, counts bool) ([]models.SmartList, int64, error) {
+	lists := []models.SmartList{}
+	var total int64
+
+	if err := s.db.Model(&models.SmartList{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := s.db.Offset(offset).Limit(limit).Order("name").Find(&lists).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if !counts {
+		return lists, total, nil
+	}
+	for i := range lists {
+		if err := s.countMembers(&lists[i]); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return lists, total, nil
+}
+
+func (s *SmartListService) GetSmartList(id uint) (*models.SmartList, error) {
+	var list models.SmartList
+	if err := s.db.First(&list, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrSmartListNotFound
+		}
+		return nil, err
+	}
+
+	if err := s.countMembers(&list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+func (s *SmartListService) CreateSmartList(req *models.CreateSmartListRequest) (*models.SmartList, error) {
+	list := models.SmartList{
+		Name:        strings.TrimSpace(req.Name),
+		Description: req.Description,
+		Definition:  normalizeDefinition(req.Definition),
+	}
+
+	if _, err := s.BuildFilter(list.Definition); err != nil {
+		return nil, err
+	}
+	if err := s.checkNameAvailable(0, list.Name); err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Create(&list).Error; err != nil {
+		return nil, err
+	}
+
+	if err := s.countMembers(&list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+func (s *SmartListService) UpdateSmartList(id uint, req *models.UpdateSmartListRequest) (*models.SmartList, error) {
+	list, err := s.GetSmartList(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		list.Name = strings.TrimSpace(*req.Name)
+		if err := s.checkNameAvailable(list.ID, list.Name); err != nil {
+			return nil, err
+		}
+	}
+	if req.Description != nil {
+		list.Description = *req.Description
+	}
+	if req.Definition != nil {
+		list.Definition = normalizeDefinition(*req.Definition)
+		if _, err := s.BuildFilter(list.Definition); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.db.Save(list).Error; err != nil {
+		return nil, err
+	}
+
+	if err := s.countMembers(list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (s *SmartListService) DeleteSmartList(id uint) error {
+	result := s.db.Delete(&models.SmartList{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrSmartListNotFound
+	}
+	return nil
+}
+
+// GetContacts evaluates a smart list through the contact search
+func (s *SmartListService) GetContacts(id uint, page, limit int) ([]models.Contact, int64, error) {
+	list, err := s.GetSmartList(id)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	filter, err := s.BuildFilter(list.Definition)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return s.contacts.SearchContacts(SearchOptions{Query: list.Definition.Query}, page, limit, filter)
+}
+
+// BuildFilter checks a definition against the current custom fields and
+// companies and turns it into the filter used by ContactService
+func (s *SmartListService) BuildFilter(definition models.SmartListDefinition) (ContactFilter, error) {
+	filter := ContactFilter{
+		Tags:      definition.Tags,
+		TagMode:   definition.TagMode,
+		CompanyID: definition.CompanyID,
+	}
+
+	var problems []string
+	if definition.Query == "" && len(definition.Tags) == 0 && definition.CompanyID == nil &&
+		len(definition.CustomFields) == 0 && len(definition.Conditions) == 0 {
+		problems = append(problems, "definition must have at least one criterion")
+	}
+
+	if definition.CompanyID != nil {
+		var count int64
+		if err := s.db.Model(&models.Company{}).Where("id = ?", *definition.CompanyID).Count(&count).Error; err != nil {
+			return filter, err
+		}
+		if count == 0 {
+			problems = append(problems, fmt.Sprintf("company %d does not exist", *definition.CompanyID))
+		}
+	}
+
+	customFilters, err := s.customFields.BuildFilters(definition.CustomFields)
+	if err != nil {
+		fieldErr, ok := err.(*CustomFieldError)
+		if !ok {
+			return filter, err
+		}
+		problems = append(problems, fieldErr.Problems...)
+	}
+	filter.CustomFields = customFilters
+
+	for _, saved := range definition.Conditions {
+		condition := ContactCondition{Field: saved.Field, Operator: saved.Operator, Value: saved.Value}
+		if problem := checkCondition(condition); problem != "" {
+			problems = append(problems, problem)
+			continue
+		}
+		filter.Conditions = append(filter.Conditions, condition)
+	}
+
+	if len(problems) > 0 {
+		return filter, &SmartListError{Problems: problems}
+	}
+	return filter, nil
+}
+
+// countMembers sets the member count of a list, or the problem found in a
+// definition broken by a later schema change, which cannot be counted
+func (s *SmartListService) countMembers(list *models.SmartList) error {
+	filter, err := s.BuildFilter(list.Definition)
+	if err != nil {
+		if _, ok := err.(*SmartListError); ok {
+			list.DefinitionError = err.Error()
+			return nil
+		}
+		return err
+	}
+
+	count, err := s.contacts.CountSearchResults(SearchOptions{Query: list.Definition.Query}, filter)
+	if err != nil {
+		return err
+	}
+	list.MemberCount = &count
+	return nil
+}
+
+func (s *SmartListService) checkNameAvailable(id uint, name string) error {
+	var count int64
+	if err := s.db.Model(&models.SmartList{}).
+		Where("LOWER(name) = ? AND id <> ?", strings.ToLower(name), id).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrSmartListExists
+	}
+	return nil
+}
+
+func normalizeDefinition(definition models.SmartListDefinition) models.SmartListDefinition {
+	definition.Query = strings.TrimSpace(definition.Query)
+	definition.Tags = NormalizeTags(definition.Tags)
+	if len(definition.Tags) == 0 {
+		definition.Tags = nil
+		definition.TagMode = ""
+	} else if definition.TagMode == "" {
+		definition.TagMode = TagMatchAny
+	}
+	return definition
+}
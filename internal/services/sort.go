@@ -0,0 +1,142 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+	"gorm.io/gorm"
+)
+
+// SortRelevance orders search results by their score
+const SortRelevance = "relevance"
+
+// MaxSortFields caps how many keys a sort can combine
+const MaxSortFields = 5
+
+// SortField orders contacts by one field, ascending unless Desc is set
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// sortColumns are the fields contacts can be sorted by. Name and company sort
+// by their folded copies, so case and accents do not affect the order.
+// PostgreSQL compares the text ones in sortCollation, see sortColumn.
+var sortColumns = map[string]string{
+	"id":          "contacts.id",
+	"name":        "contacts.name_key",
+	"email":       "LOWER(contacts.email)",
+	"company":     "contacts.company_key",
+	"created_at":  "contacts.created_at",
+	"updated_at":  "contacts.updated_at",
+	SortRelevance: "score",
+}
+
+// sortCollation is the collation PostgreSQL sorts names, companies and
+// emails in: the root collation of ICU, which newSortCollator follows for the
+// matches a search index sorts in Go. name_key and company_key are declared
+// with it, while emails take it in the sort expression.
+const sortCollation = `"und-x-icu"`
+
+// newSortCollator compares text in the order of sortCollation
+func newSortCollator() *collate.Collator {
+	return collate.New(language.Und)
+}
+
+// sortColumn is the column or expression a field sorts by in db
+func sortColumn(db *gorm.DB, field string) string {
+	if field == "email" && isPostgres(db) {
+		return sortColumns[field] + " COLLATE " + sortCollation
+	}
+	return sortColumns[field]
+}
+
+var (
+	// DefaultContactSort orders listings, newest first
+	DefaultContactSort = []SortField{{Field: "created_at", Desc: true}}
+	// DefaultSearchSort orders searches, most relevant first
+	DefaultSearchSort = []SortField{{Field: SortRelevance, Desc: true}, {Field: "created_at", Desc: true}}
+)
+
+// SortError is a sort parameter naming an unknown or repeated field
+type SortError struct {
+	message string
+}
+
+func (e *SortError) Error() string {
+	return e.message
+}
+
+// ParseSort reads a comma-separated list of fields such as "name,-created_at",
+// where a leading minus sorts descending. Relevance only applies to searches.
+func ParseSort(value string, search bool) ([]SortField, error) {
+	var fields []SortField
+	seen := map[string]bool{}
+
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		field := SortField{Field: strings.TrimPrefix(part, "-"), Desc: strings.HasPrefix(part, "-")}
+
+		if _, ok := sortColumns[field.Field]; !ok || field.Field == SortRelevance && !search {
+			return nil, &SortError{fmt.Sprintf("cannot sort by '%s', use one of: %s", field.Field, strings.Join(sortFieldNames(search), ", "))}
+		}
+		if seen[field.Field] {
+			return nil, &SortError{fmt.Sprintf("'%s' appears more than once in sort", field.Field)}
+		}
+		seen[field.Field] = true
+		fields = append(fields, field)
+	}
+
+	if len(fields) > MaxSortFields {
+		return nil, &SortError{fmt.Sprintf("sort accepts at most %d fields", MaxSortFields)}
+	}
+	return fields, nil
+}
+
+// FormatSort renders a sort the way ParseSort reads it
+func FormatSort(fields []SortField) string {
+	parts := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if field.Desc {
+			parts = append(parts, "-"+field.Field)
+		} else {
+			parts = append(parts, field.Field)
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+func sortFieldNames(search bool) []string {
+	names := []string{"name", "email", "company", "created_at", "updated_at", "id"}
+	if search {
+		names = append(names, SortRelevance)
+	}
+	return names
+}
+
+// applySort orders a contacts query by the given fields, then by id in the
+// direction of the last field so that pages never overlap. Relevance is
+// skipped when the query has no score.
+func applySort(query *gorm.DB, fields []SortField, scored bool) *gorm.DB {
+	desc := false
+	for _, field := range fields {
+		if field.Field == SortRelevance && !scored {
+			continue
+		}
+		query = query.Order(sortColumn(query, field.Field) + direction(field.Desc))
+		desc = field.Desc
+		if field.Field == "id" {
+			return query
+		}
+	}
+	return query.Order("contacts.id" + direction(desc))
+}
+
+func direction(desc bool) string {
+	if desc {
+		return " DESC"
+	}
+	return " ASC"
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"api-contacts-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+var ErrTagNotFound = errors.New("tag not found")
+
+type TagService struct {
+	db    *gorm.DB
+	index SearchIndex
+}
+
+// NewTagService creates a service keeping index in step with the updated_at
+// of tagged contacts, which searches sort by, or no index when nil
+func NewTagService(db *gorm.DB, index SearchIndex) *TagService {
+	if index == nil {
+		index = SQLIndex{}
+	}
+	return &TagService{db: db, index: index}
+}
+
+// NormalizeTags lowercases, trims and de-duplicates tag names, dropping empty ones
+func NormalizeTags(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	tags := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		tags = append(tags, name)
+	}
+	return tags
+}
+
+func (s *TagService) ListTags() ([]models.TagResponse, error) {
+	var tags []models.TagResponse
+
+	// Soft-deleted contacts keep their tag links, so they must not be counted
+	err := s.db.Model(&models.Tag{}).
+		Select("tags.id, tags.name, COUNT(contacts.id) AS contact_count").
+		Joins("LEFT JOIN contact_tags ON contact_tags.tag_id = tags.id").
+		Joins("LEFT JOIN contacts ON contacts.id = contact_tags.contact_id AND contacts.deleted_at IS NULL").
+		Group("tags.id, tags.name").
+		Order("tags.name").
+		Scan(&tags).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+func (s *TagService) GetContactTags(contactID uint) ([]models.Tag, error) {
+	var contact models.Contact
+	if err := s.db.First(&contact, contactID).Error; err != nil {
+		return nil, err
+	}
+
+	tags := []models.Tag{}
+	if err := s.db.Model(&contact).Order("tags.name").Association("Tags").Find(&tags); err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+func (s *TagService) AddContactTags(contactID uint, names []string) ([]models.Tag, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var contact models.Contact
+		if err := tx.First(&contact, contactID).Error; err != nil {
+			return err
+		}
+
+		tags := make([]models.Tag, 0, len(names))
+		for _, name := range NormalizeTags(names) {
+			tag := models.Tag{Name: name}
+			if err := tx.Where("name = ?", name).FirstOrCreate(&tag).Error; err != nil {
+				return err
+			}
+			tags = append(tags, tag)
+		}
+
+		// Appending touches the contact's updated_at
+		if err := tx.Model(&contact).Association("Tags").Append(tags); err != nil {
+			return err
+		}
+		return queueIndexUpdates(tx, s.index, contact.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	refreshSearchIndex(s.db, s.index, contactID)
+
+	return s.GetContactTags(contactID)
+}
+
+func (s *TagService) RemoveContactTag(contactID uint, name string) error {
+	var contact models.Contact
+	if err := s.db.First(&contact, contactID).Error; err != nil {
+		return err
+	}
+
+	var tag models.Tag
+	if err := s.db.Where("name = ?", strings.ToLower(strings.TrimSpace(name))).First(&tag).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return ErrTagNotFound
+		}
+		return err
+	}
+
+	return s.db.Model(&contact).Association("Tags").Delete(&tag)
+}
@@ -0,0 +1,227 @@
+package services
+
+import (
+	"strings"
+	"unicode"
+
+	"api-contacts-go/internal/models"
+)
+
+// searchToken is a word of a text as the text search sees it, folded, with
+// the range of characters it spans in the original text
+type searchToken struct {
+	word       string
+	start, end int
+}
+
+// searchTokens splits text into the words the PostgreSQL parser of
+// to_tsvector('simple', ...) finds in it: runs of letters and digits, plus
+// whole email addresses, ignoring case and accents
+func searchTokens(text string) []searchToken {
+	runes := []rune(text)
+	return append(emailTokens(runes), wordTokens(runes)...)
+}
+
+// wordTokens finds the runs of letters and digits, keeping accents written as
+// separate combining marks inside the word
+func wordTokens(runes []rune) []searchToken {
+	var tokens []searchToken
+	for start := 0; start < len(runes); {
+		if !isWordRune(runes[start]) {
+			start++
+			continue
+		}
+		end := start
+		for end < len(runes) && (isWordRune(runes[end]) || unicode.Is(unicode.Mn, runes[end])) {
+			end++
+		}
+		tokens = append(tokens, searchToken{word: models.FoldText(string(runes[start:end])), start: start, end: end})
+		start = end
+	}
+	return tokens
+}
+
+// emailTokens finds the whitespace-separated parts of the text holding an
+// email address, without the punctuation around them
+func emailTokens(runes []rune) []searchToken {
+	var tokens []searchToken
+	for start := 0; start < len(runes); {
+		if unicode.IsSpace(runes[start]) {
+			start++
+			continue
+		}
+		end := start
+		for end < len(runes) && !unicode.IsSpace(runes[end]) {
+			end++
+		}
+
+		first, last := start, end
+		for first < last && !isWordRune(runes[first]) {
+			first++
+		}
+		for last > first && !isWordRune(runes[last-1]) {
+			last--
+		}
+		if part := string(runes[first:last]); strings.Contains(part, "@") {
+			tokens = append(tokens, searchToken{word: models.FoldText(part), start: first, end: last})
+		}
+		start = end
+	}
+	return tokens
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// queryTerm is a word of a text query, which matching contacts must not have
+// when negated
+type queryTerm struct {
+	word    string
+	negated bool
+}
+
+// textQuery is a text query parsed like websearch_to_tsquery does: every
+// group must match, a group matching when any of its terms, joined by "or",
+// does. Quoted phrases are matched word by word.
+type textQuery [][]queryTerm
+
+// parseTextQuery reads a text query. Email addresses stay whole, every other
+// word is split like searchTokens splits the searched text.
+func parseTextQuery(query string) textQuery {
+	fields := strings.Fields(strings.NewReplacer(`"`, " ").Replace(query))
+
+	var parsed textQuery
+	join := false
+	for i, field := range fields {
+		if strings.EqualFold(field, "or") && len(parsed) > 0 && i < len(fields)-1 {
+			join = true
+			continue
+		}
+
+		negated := len(field) > 1 && field[0] == '-'
+		if negated {
+			field = field[1:]
+		}
+		tokens := emailTokens([]rune(field))
+		if len(tokens) == 0 {
+			tokens = wordTokens([]rune(field))
+		}
+
+		for j, token := range tokens {
+			term := queryTerm{word: token.word, negated: negated}
+			if j == 0 && join {
+				parsed[len(parsed)-1] = append(parsed[len(parsed)-1], term)
+				continue
+			}
+			parsed = append(parsed, []queryTerm{term})
+		}
+		join = false
+	}
+	return parsed
+}
+
+// words lists the words the query looks for, leaving out negated ones
+func (q textQuery) words() map[string]bool {
+	words := map[string]bool{}
+	for _, group := range q {
+		for _, term := range group {
+			if !term.negated {
+				words[term.word] = true
+			}
+		}
+	}
+	return words
+}
+
+// Weights of a word found in each field, the ones ts_rank gives to name (A),
+// company (B), primary email (C) and the other emails and phones (D)
+const (
+	nameWeight    = 1.0
+	companyWeight = 0.4
+	emailWeight   = 0.2
+	methodsWeight = 0.1
+)
+
+// searchDocument holds the words of each searched field of a contact, with
+// the weight of the field
+type searchDocument map[string]float64
+
+// newSearchDocument collects the words of a contact from its folded name and
+// company, primary email and methods key
+func newSearchDocument(nameKey, companyKey, email, methodsKey string) searchDocument {
+	document := searchDocument{}
+	addField := func(text string, weight float64) {
+		seen := map[string]bool{}
+		for _, token := range searchTokens(text) {
+			if !seen[token.word] {
+				seen[token.word] = true
+				document[token.word] += weight
+			}
+		}
+	}
+
+	addField(nameKey, nameWeight)
+	addField(companyKey, companyWeight)
+	addField(email, emailWeight)
+	addField(methodsKey, methodsWeight)
+	return document
+}
+
+// weight looks up the weight of a word in the document
+func (d searchDocument) weight(word string) (float64, bool) {
+	weight, ok := d[word]
+	return weight, ok
+}
+
+// score reports whether a document, whose words weight looks up, matches the
+// query and sums, for each group, the weight of the best term found
+func (q textQuery) score(weight func(word string) (float64, bool)) (float64, bool) {
+	if len(q) == 0 {
+		return 0, false
+	}
+
+	score := 0.0
+	for _, group := range q {
+		matched := false
+		best := 0.0
+		for _, term := range group {
+			found, ok := weight(term.word)
+			if term.negated {
+				found, ok = 0, !ok
+			}
+			if ok {
+				matched = true
+				best = max(best, found)
+			}
+		}
+		if !matched {
+			return 0, false
+		}
+		score += best
+	}
+	return score, true
+}
+
+// phoneScore scores the phone numbers of a methods key against the digits of
+// a phone query as newPhoneSearch does: 1 for the whole number, 0.5 for part
+// of one and 0 when none has the digits
+func phoneScore(methodsKey, digits string) float64 {
+	if digits == "" {
+		return 0
+	}
+
+	score := 0.0
+	for _, word := range strings.Fields(methodsKey) {
+		if strings.Trim(word, "0123456789") != "" {
+			continue
+		}
+		if word == digits {
+			return 1.0
+		}
+		if strings.Contains(word, digits) {
+			score = 0.5
+		}
+	}
+	return score
+}
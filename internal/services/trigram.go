@@ -0,0 +1,93 @@
+package services
+
+import (
+	"strings"
+	"unicode"
+)
+
+// trigramSequence lists the trigrams pg_trgm extracts from text, word after
+// word: each word is lowercased and padded with two spaces in front and one
+// behind
+func trigramSequence(text string) []string {
+	var sequence []string
+	separator := func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}
+
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), separator) {
+		padded := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			sequence = append(sequence, string(padded[i:i+3]))
+		}
+	}
+	return sequence
+}
+
+// trigrams is the set of trigrams of text
+func trigrams(text string) map[string]bool {
+	set := map[string]bool{}
+	for _, trigram := range trigramSequence(text) {
+		set[trigram] = true
+	}
+	return set
+}
+
+// wordSimilarity is pg_trgm's word_similarity, from 0 to 1: the greatest
+// similarity of the query trigrams to an extent of the text trigrams. Like
+// pg_trgm, it grows the extent up to each trigram shared with the query, then
+// moves its lower bound forward while that raises the similarity.
+func wordSimilarity(query, text string) float64 {
+	queryTrigrams := trigrams(query)
+	if len(queryTrigrams) == 0 {
+		return 0
+	}
+	similarity := func(shared, unique int) float64 {
+		return float64(shared) / float64(len(queryTrigrams)+unique-shared)
+	}
+
+	sequence := trigramSequence(text)
+	// Last position of each trigram of the extent
+	last := map[string]int{}
+	lower, shared, unique := -1, 0, 0
+	best := 0.0
+	for i, trigram := range sequence {
+		found := queryTrigrams[trigram]
+		if lower >= 0 || found {
+			if _, ok := last[trigram]; !ok {
+				unique++
+				if found {
+					shared++
+				}
+			}
+			last[trigram] = i
+		}
+		if !found {
+			continue
+		}
+		if lower < 0 {
+			lower = i
+		}
+
+		current := similarity(shared, unique)
+		previous, tryShared, tryUnique := lower, shared, unique
+		for try := lower; try <= i; try++ {
+			if s := similarity(tryShared, tryUnique); s > current {
+				current, lower, shared, unique = s, try, tryShared, tryUnique
+			}
+			if dropped := sequence[try]; last[dropped] == try {
+				tryUnique--
+				if queryTrigrams[dropped] {
+					tryShared--
+				}
+			}
+		}
+		best = max(best, current)
+
+		for j := previous; j < lower; j++ {
+			if last[sequence[j]] == j {
+				delete(last, sequence[j])
+			}
+		}
+	}
+	return best
+}
@@ -0,0 +1,6 @@
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_contact_tags_tag_id;
+DROP TABLE IF EXISTS contact_tags;
+DROP TABLE IF EXISTS tags;
+-- +goose StatementEnd
@@ -0,0 +1,8 @@
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_contact_phones_contact_id;
+DROP INDEX IF EXISTS idx_contact_emails_contact_id;
+DROP INDEX IF EXISTS idx_contact_emails_email;
+DROP TABLE IF EXISTS contact_phones;
+DROP TABLE IF EXISTS contact_emails;
+-- +goose StatementEnd
@@ -0,0 +1,55 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS contact_emails (
+    id SERIAL PRIMARY KEY,
+    contact_id INTEGER NOT NULL REFERENCES contacts(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    label VARCHAR(20) NOT NULL DEFAULT 'other',
+    is_primary BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS contact_phones (
+    id SERIAL PRIMARY KEY,
+    contact_id INTEGER NOT NULL REFERENCES contacts(id) ON DELETE CASCADE,
+    number VARCHAR(20) NOT NULL,
+    label VARCHAR(20) NOT NULL DEFAULT 'other',
+    is_primary BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Emails are unique across every contact, regardless of case. contacts.email
+-- only was unique as typed, so addresses differing in case are reported
+-- instead of picking which contact keeps them.
+DO $$
+DECLARE
+    duplicates TEXT;
+BEGIN
+    SELECT string_agg(address, ', ') INTO duplicates
+    FROM (
+        SELECT LOWER(email) AS address FROM contacts
+        WHERE deleted_at IS NULL
+        GROUP BY LOWER(email) HAVING COUNT(*) > 1
+    ) AS taken;
+
+    IF duplicates IS NOT NULL THEN
+        RAISE EXCEPTION 'contacts share emails differing only in case, fix them before migrating: %', duplicates;
+    END IF;
+END $$;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_contact_emails_email ON contact_emails(LOWER(email));
+CREATE INDEX IF NOT EXISTS idx_contact_emails_contact_id ON contact_emails(contact_id);
+CREATE INDEX IF NOT EXISTS idx_contact_phones_contact_id ON contact_phones(contact_id);
+
+-- The scalar columns become the primary entries of existing contacts. Deleted
+-- contacts are left out: their addresses stay taken through contacts.email,
+-- which the services check regardless of deleted_at.
+INSERT INTO contact_emails (contact_id, email, label, is_primary)
+SELECT id, email, 'other', TRUE FROM contacts WHERE deleted_at IS NULL;
+
+INSERT INTO contact_phones (contact_id, number, label, is_primary)
+SELECT id, phone, 'other', TRUE FROM contacts
+WHERE phone IS NOT NULL AND phone <> '' AND deleted_at IS NULL;
+-- +goose StatementEnd
@@ -0,0 +1,5 @@
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_addresses_contact_id;
+DROP TABLE IF EXISTS addresses;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS addresses (
+    id SERIAL PRIMARY KEY,
+    contact_id INTEGER NOT NULL REFERENCES contacts(id) ON DELETE CASCADE,
+    label VARCHAR(20) NOT NULL DEFAULT 'other',
+    street VARCHAR(200) NOT NULL,
+    number VARCHAR(20),
+    complement VARCHAR(100),
+    district VARCHAR(100),
+    city VARCHAR(100) NOT NULL,
+    state VARCHAR(50),
+    postal_code VARCHAR(20) NOT NULL,
+    country CHAR(2) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_addresses_contact_id ON addresses(contact_id);
+-- +goose StatementEnd
@@ -0,0 +1,5 @@
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_notes_contact_id;
+DROP TABLE IF EXISTS notes;
+-- +goose StatementEnd
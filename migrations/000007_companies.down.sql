@@ -0,0 +1,6 @@
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_contacts_company_id;
+ALTER TABLE contacts DROP COLUMN IF EXISTS company_id;
+DROP TABLE IF EXISTS companies;
+-- +goose StatementEnd
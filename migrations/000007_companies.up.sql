@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS companies (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    normalized_name VARCHAR(100) UNIQUE NOT NULL,
+    website VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+ALTER TABLE contacts ADD COLUMN IF NOT EXISTS company_id INTEGER REFERENCES companies(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_contacts_company_id ON contacts(company_id);
+
+-- Back-fill one company per normalized name (lowercase letters and digits,
+-- see models.NormalizeCompanyName), named after its most used spelling
+INSERT INTO companies (name, normalized_name)
+SELECT DISTINCT ON (normalized_name) name, normalized_name
+FROM (
+    SELECT TRIM(company) AS name,
+           regexp_replace(LOWER(company), '[^[:alnum:]]', '', 'g') AS normalized_name,
+           COUNT(*) AS uses
+    FROM contacts
+    WHERE company IS NOT NULL AND TRIM(company) <> ''
+    GROUP BY 1, 2
+) AS spellings
+WHERE normalized_name <> ''
+ORDER BY normalized_name, uses DESC, name
+ON CONFLICT (normalized_name) DO NOTHING;
+
+UPDATE contacts
+SET company_id = companies.id,
+    company = companies.name
+FROM companies
+WHERE companies.normalized_name = regexp_replace(LOWER(contacts.company), '[^[:alnum:]]', '', 'g');
+-- +goose StatementEnd
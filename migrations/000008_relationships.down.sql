@@ -0,0 +1,7 @@
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_relationships_type;
+DROP INDEX IF EXISTS idx_relationships_related_contact_id;
+DROP INDEX IF EXISTS idx_relationships_unique;
+DROP TABLE IF EXISTS relationships;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS relationships (
+    id SERIAL PRIMARY KEY,
+    contact_id INTEGER NOT NULL REFERENCES contacts(id) ON DELETE CASCADE,
+    related_contact_id INTEGER NOT NULL REFERENCES contacts(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL,
+    label VARCHAR(50) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CHECK (contact_id <> related_contact_id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_relationships_unique ON relationships(contact_id, related_contact_id, type, label);
+CREATE INDEX IF NOT EXISTS idx_relationships_related_contact_id ON relationships(related_contact_id);
+CREATE INDEX IF NOT EXISTS idx_relationships_type ON relationships(type);
+-- +goose StatementEnd
@@ -0,0 +1,7 @@
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_contact_list_members_contact_id;
+DROP TABLE IF EXISTS contact_list_members;
+DROP INDEX IF EXISTS idx_contact_lists_name;
+DROP TABLE IF EXISTS contact_lists;
+-- +goose StatementEnd
@@ -0,0 +1,5 @@
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_smart_lists_name;
+DROP TABLE IF EXISTS smart_lists;
+-- +goose StatementEnd
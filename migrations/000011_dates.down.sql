@@ -0,0 +1,10 @@
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_significant_dates_month_day;
+DROP INDEX IF EXISTS idx_significant_dates_contact_id;
+DROP TABLE IF EXISTS significant_dates;
+DROP INDEX IF EXISTS idx_contacts_birthday;
+ALTER TABLE contacts DROP COLUMN IF EXISTS birth_day;
+ALTER TABLE contacts DROP COLUMN IF EXISTS birth_month;
+ALTER TABLE contacts DROP COLUMN IF EXISTS birth_year;
+-- +goose StatementEnd
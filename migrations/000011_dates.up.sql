@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE contacts ADD COLUMN IF NOT EXISTS birth_year SMALLINT;
+ALTER TABLE contacts ADD COLUMN IF NOT EXISTS birth_month SMALLINT CHECK (birth_month BETWEEN 1 AND 12);
+ALTER TABLE contacts ADD COLUMN IF NOT EXISTS birth_day SMALLINT CHECK (birth_day BETWEEN 1 AND 31);
+
+CREATE INDEX IF NOT EXISTS idx_contacts_birthday ON contacts(birth_month, birth_day) WHERE birth_month IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS significant_dates (
+    id SERIAL PRIMARY KEY,
+    contact_id INTEGER NOT NULL REFERENCES contacts(id) ON DELETE CASCADE,
+    label VARCHAR(50) NOT NULL,
+    year SMALLINT,
+    month SMALLINT NOT NULL CHECK (month BETWEEN 1 AND 12),
+    day SMALLINT NOT NULL CHECK (day BETWEEN 1 AND 31),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_significant_dates_contact_id ON significant_dates(contact_id);
+CREATE INDEX IF NOT EXISTS idx_significant_dates_month_day ON significant_dates(month, day);
+-- +goose StatementEnd
@@ -0,0 +1,5 @@
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_contacts_search_vector;
+ALTER TABLE contacts DROP COLUMN IF EXISTS search_vector;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Name weighs the most, then company, then the primary email. Emails are also
+-- indexed with "@" and "." as separators so their parts can be searched.
+ALTER TABLE contacts ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', coalesce(name, '')), 'A') ||
+    setweight(to_tsvector('simple', coalesce(company, '')), 'B') ||
+    setweight(to_tsvector('simple', coalesce(email, '')), 'C') ||
+    setweight(to_tsvector('simple', translate(coalesce(email, ''), '@.', '  ')), 'C')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_contacts_search_vector ON contacts USING GIN(search_vector);
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_contacts_search_vector;
+ALTER TABLE contacts DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE contacts ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', coalesce(name, '')), 'A') ||
+    setweight(to_tsvector('simple', coalesce(company, '')), 'B') ||
+    setweight(to_tsvector('simple', coalesce(email, '')), 'C') ||
+    setweight(to_tsvector('simple', translate(coalesce(email, ''), '@.', '  ')), 'C')
+) STORED;
+CREATE INDEX IF NOT EXISTS idx_contacts_search_vector ON contacts USING GIN(search_vector);
+
+DROP INDEX IF EXISTS idx_companies_sort_name;
+ALTER TABLE companies DROP COLUMN IF EXISTS sort_name;
+DROP INDEX IF EXISTS idx_contacts_name_key;
+ALTER TABLE contacts DROP COLUMN IF EXISTS company_key;
+ALTER TABLE contacts DROP COLUMN IF EXISTS name_key;
+DROP FUNCTION IF EXISTS f_unaccent(text);
+-- +goose StatementEnd
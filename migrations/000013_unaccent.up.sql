@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+-- unaccent() is only STABLE, so wrap it with a fixed dictionary to use it in
+-- generated columns and indexes
+CREATE OR REPLACE FUNCTION f_unaccent(text) RETURNS text AS $$
+    SELECT public.unaccent('public.unaccent', $1)
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT;
+
+-- Folded copies of name and company, kept in sync by the application
+ALTER TABLE contacts ADD COLUMN IF NOT EXISTS name_key VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE contacts ADD COLUMN IF NOT EXISTS company_key VARCHAR(100) NOT NULL DEFAULT '';
+UPDATE contacts SET name_key = lower(f_unaccent(name)), company_key = lower(f_unaccent(coalesce(company, '')));
+CREATE INDEX IF NOT EXISTS idx_contacts_name_key ON contacts(name_key);
+
+ALTER TABLE companies ADD COLUMN IF NOT EXISTS sort_name VARCHAR(100) NOT NULL DEFAULT '';
+UPDATE companies SET sort_name = lower(f_unaccent(name));
+CREATE INDEX IF NOT EXISTS idx_companies_sort_name ON companies(sort_name);
+
+-- Rebuild the search vector without accents
+DROP INDEX IF EXISTS idx_contacts_search_vector;
+ALTER TABLE contacts DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE contacts ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', f_unaccent(coalesce(name, ''))), 'A') ||
+    setweight(to_tsvector('simple', f_unaccent(coalesce(company, ''))), 'B') ||
+    setweight(to_tsvector('simple', coalesce(email, '')), 'C') ||
+    setweight(to_tsvector('simple', translate(coalesce(email, ''), '@.', '  ')), 'C')
+) STORED;
+CREATE INDEX IF NOT EXISTS idx_contacts_search_vector ON contacts USING GIN(search_vector);
+-- +goose StatementEnd
@@ -0,0 +1,6 @@
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_contacts_email_trgm;
+DROP INDEX IF EXISTS idx_contacts_company_key_trgm;
+DROP INDEX IF EXISTS idx_contacts_name_key_trgm;
+-- +goose StatementEnd
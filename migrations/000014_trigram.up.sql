@@ -0,0 +1,8 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_contacts_name_key_trgm ON contacts USING GIN(name_key gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_contacts_company_key_trgm ON contacts USING GIN(company_key gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_contacts_email_trgm ON contacts USING GIN(LOWER(email) gin_trgm_ops);
+-- +goose StatementEnd
@@ -0,0 +1,6 @@
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_contacts_phonetic_key_trgm;
+DROP INDEX IF EXISTS idx_contacts_phonetic_key;
+ALTER TABLE contacts DROP COLUMN IF EXISTS phonetic_key;
+-- +goose StatementEnd
@@ -0,0 +1,8 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Sound-alike code of each word of the name, computed by the application.
+-- Existing contacts are filled in when the server starts.
+ALTER TABLE contacts ADD COLUMN IF NOT EXISTS phonetic_key VARCHAR(200) NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_contacts_phonetic_key ON contacts(phonetic_key);
+CREATE INDEX IF NOT EXISTS idx_contacts_phonetic_key_trgm ON contacts USING GIN(phonetic_key gin_trgm_ops);
+-- +goose StatementEnd
@@ -0,0 +1,6 @@
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_contact_phones_digits_trgm;
+DROP INDEX IF EXISTS idx_contact_phones_digits;
+ALTER TABLE contact_phones DROP COLUMN IF EXISTS digits;
+-- +goose StatementEnd
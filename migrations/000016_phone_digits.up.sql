@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Digits of each number without the Brazilian country code, kept in sync by
+-- the application
+ALTER TABLE contact_phones ADD COLUMN IF NOT EXISTS digits VARCHAR(20) NOT NULL DEFAULT '';
+
+UPDATE contact_phones SET digits = regexp_replace(number, '[^0-9]', '', 'g');
+UPDATE contact_phones SET digits = substr(digits, 3) WHERE length(digits) >= 12 AND digits LIKE '55%';
+
+CREATE INDEX IF NOT EXISTS idx_contact_phones_digits ON contact_phones(digits);
+CREATE INDEX IF NOT EXISTS idx_contact_phones_digits_trgm ON contact_phones USING GIN(digits gin_trgm_ops);
+-- +goose StatementEnd
@@ -0,0 +1,8 @@
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_contacts_email_id;
+DROP INDEX IF EXISTS idx_contacts_company_key_id;
+DROP INDEX IF EXISTS idx_contacts_name_key_id;
+DROP INDEX IF EXISTS idx_contacts_updated_at_id;
+DROP INDEX IF EXISTS idx_contacts_created_at_id;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Cursor pagination reads each sort order from an index ending with the id
+-- tie-break, skipping soft-deleted contacts
+CREATE INDEX IF NOT EXISTS idx_contacts_created_at_id ON contacts(created_at, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_contacts_updated_at_id ON contacts(updated_at, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_contacts_name_key_id ON contacts(name_key, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_contacts_company_key_id ON contacts(company_key, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_contacts_email_id ON contacts(LOWER(email), id) WHERE deleted_at IS NULL;
+-- +goose StatementEnd
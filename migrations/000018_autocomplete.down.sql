@@ -0,0 +1,6 @@
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_contacts_email_prefix;
+DROP INDEX IF EXISTS idx_contacts_name_key_prefix;
+ALTER TABLE contacts DROP COLUMN IF EXISTS selection_count;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- How often each contact was picked from autocomplete suggestions
+ALTER TABLE contacts ADD COLUMN IF NOT EXISTS selection_count INTEGER NOT NULL DEFAULT 0;
+
+-- Prefix LIKE patterns can only use a btree index with pattern operators,
+-- whatever the database collation
+CREATE INDEX IF NOT EXISTS idx_contacts_name_key_prefix ON contacts(name_key text_pattern_ops) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_contacts_email_prefix ON contacts(LOWER(email) text_pattern_ops) WHERE deleted_at IS NULL;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_contacts_search_vector;
+ALTER TABLE contacts DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE contacts ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', f_unaccent(coalesce(name, ''))), 'A') ||
+    setweight(to_tsvector('simple', f_unaccent(coalesce(company, ''))), 'B') ||
+    setweight(to_tsvector('simple', coalesce(email, '')), 'C') ||
+    setweight(to_tsvector('simple', translate(coalesce(email, ''), '@.', '  ')), 'C')
+) STORED;
+CREATE INDEX IF NOT EXISTS idx_contacts_search_vector ON contacts USING GIN(search_vector);
+
+ALTER TABLE contacts DROP COLUMN IF EXISTS methods_key;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Secondary emails and the digits of every phone number, kept in sync by the
+-- application, so that the search vector covers them and text searches never
+-- leave its GIN index
+ALTER TABLE contacts ADD COLUMN IF NOT EXISTS methods_key TEXT NOT NULL DEFAULT '';
+UPDATE contacts SET methods_key = lower(concat_ws(' ',
+    (SELECT string_agg(email, ' ' ORDER BY id) FROM contact_emails WHERE contact_id = contacts.id AND NOT is_primary),
+    (SELECT string_agg(digits, ' ' ORDER BY id) FROM contact_phones WHERE contact_id = contacts.id)
+));
+
+DROP INDEX IF EXISTS idx_contacts_search_vector;
+ALTER TABLE contacts DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE contacts ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', f_unaccent(coalesce(name, ''))), 'A') ||
+    setweight(to_tsvector('simple', f_unaccent(coalesce(company, ''))), 'B') ||
+    setweight(to_tsvector('simple', coalesce(email, '')), 'C') ||
+    setweight(to_tsvector('simple', translate(coalesce(email, ''), '@.', '  ')), 'C') ||
+    setweight(to_tsvector('simple', methods_key), 'D') ||
+    setweight(to_tsvector('simple', translate(methods_key, '@.', '  ')), 'D')
+) STORED;
+CREATE INDEX IF NOT EXISTS idx_contacts_search_vector ON contacts USING GIN(search_vector);
+-- +goose StatementEnd
@@ -0,0 +1,6 @@
+-- +goose Down
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS idx_contacts_phonetic_key_trgm ON contacts USING GIN(phonetic_key gin_trgm_ops);
+DROP INDEX IF EXISTS idx_contacts_phonetic_vector;
+ALTER TABLE contacts DROP COLUMN IF EXISTS phonetic_vector;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Phonetic searches look each sound-alike code up as a word of this vector,
+-- which the GIN index answers, instead of matching patterns on phonetic_key
+ALTER TABLE contacts ADD COLUMN IF NOT EXISTS phonetic_vector tsvector GENERATED ALWAYS AS (
+    to_tsvector('simple', phonetic_key)
+) STORED;
+CREATE INDEX IF NOT EXISTS idx_contacts_phonetic_vector ON contacts USING GIN(phonetic_vector);
+DROP INDEX IF EXISTS idx_contacts_phonetic_key_trgm;
+-- +goose StatementEnd
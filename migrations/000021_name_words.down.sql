@@ -0,0 +1,4 @@
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS contact_name_words;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Every word of each name, folded and kept in sync by the application, so
+-- that autocomplete finds words inside names with a prefix range scan
+CREATE TABLE IF NOT EXISTS contact_name_words (
+    contact_id INTEGER NOT NULL REFERENCES contacts(id) ON DELETE CASCADE,
+    word VARCHAR(100) NOT NULL,
+    PRIMARY KEY (contact_id, word)
+);
+
+INSERT INTO contact_name_words (contact_id, word)
+SELECT DISTINCT contacts.id, words.word
+FROM contacts, regexp_split_to_table(contacts.name_key, '[^[:alnum:]]+') AS words(word)
+WHERE words.word <> '' AND contacts.deleted_at IS NULL
+ON CONFLICT DO NOTHING;
+
+CREATE INDEX IF NOT EXISTS idx_contact_name_words_prefix ON contact_name_words(word text_pattern_ops);
+-- +goose StatementEnd
@@ -0,0 +1,4 @@
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS search_index_updates;
+-- +goose StatementEnd
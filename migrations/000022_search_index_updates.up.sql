@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Contacts changed since the search index last saw them, marked in the
+-- transaction that changed them. There is no foreign key, since the marks of
+-- deleted contacts are what gets them out of the index.
+CREATE TABLE IF NOT EXISTS search_index_updates (
+    contact_id INTEGER PRIMARY KEY,
+    revision BIGINT NOT NULL DEFAULT 1
+);
+-- +goose StatementEnd
@@ -0,0 +1,5 @@
+-- +goose Down
+-- +goose StatementBegin
+-- Computed again by the server, with the rules it runs
+UPDATE contacts SET phonetic_key = '' WHERE name ~ '[çÇ]';
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The phonetic key now reads ç as s. Clearing the keys of names with a
+-- cedilla gets them computed again when the server starts, and the marks
+-- refresh the search index entries built from them.
+UPDATE contacts SET phonetic_key = '' WHERE name ~ '[çÇ]';
+
+INSERT INTO search_index_updates (contact_id)
+SELECT id FROM contacts WHERE name ~ '[çÇ]'
+ON CONFLICT (contact_id) DO UPDATE SET revision = search_index_updates.revision + 1;
+-- +goose StatementEnd
@@ -0,0 +1,8 @@
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_contacts_email_id;
+CREATE INDEX IF NOT EXISTS idx_contacts_email_id ON contacts(LOWER(email), id) WHERE deleted_at IS NULL;
+
+ALTER TABLE contacts ALTER COLUMN company_key TYPE VARCHAR(100) COLLATE "default";
+ALTER TABLE contacts ALTER COLUMN name_key TYPE VARCHAR(100) COLLATE "default";
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Names, companies and emails sort in the root collation of ICU, the same
+-- whatever the locale of the database, which the search index follows in Go
+ALTER TABLE contacts ALTER COLUMN name_key TYPE VARCHAR(100) COLLATE "und-x-icu";
+ALTER TABLE contacts ALTER COLUMN company_key TYPE VARCHAR(100) COLLATE "und-x-icu";
+
+DROP INDEX IF EXISTS idx_contacts_email_id;
+CREATE INDEX IF NOT EXISTS idx_contacts_email_id ON contacts((LOWER(email) COLLATE "und-x-icu"), id) WHERE deleted_at IS NULL;
+-- +goose StatementEnd
@@ -0,0 +1,4 @@
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_contacts_boost;
+-- +goose StatementEnd
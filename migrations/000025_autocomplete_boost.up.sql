@@ -0,0 +1,6 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Autocomplete reads the most picked and recently updated contacts in this
+-- order, besides the prefix ranges
+CREATE INDEX IF NOT EXISTS idx_contacts_boost ON contacts(selection_count DESC, updated_at DESC, id) WHERE deleted_at IS NULL;
+-- +goose StatementEnd
@@ -3,11 +3,11 @@ package main
 import (
 	"fmt"
 	"log"
-	"os"
 
 	"api-contacts-go/internal/config"
 	"api-contacts-go/internal/database"
 	"api-contacts-go/internal/models"
+	"api-contacts-go/internal/services"
 
 	"gorm.io/gorm"
 )
@@ -102,9 +102,12 @@ func seedContacts(db *gorm.DB) error {
 		},
 	}
 
-	// Insert contacts
+	// Insert contacts through the service so companies, emails and phones are
+	// linked. A search index is built from them by the server on its first
+	// start, or by cmd/reindex.
+	service := services.NewContactService(db, nil)
 	for _, contact := range contacts {
-		if err := db.Create(&contact).Error; err != nil {
+		if err := service.CreateContact(&contact); err != nil {
 			return fmt.Errorf("failed to create contact %s: %w", contact.Name, err)
 		}
 	}
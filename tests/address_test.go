@@ -0,0 +1,104 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"api-contacts-go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContactAddresses(t *testing.T) {
+	db := setupTestDB()
+	app := setupTestApp(db)
+
+	contact := &models.Contact{Name: "Test User", Email: "test@example.com"}
+	db.Create(contact)
+
+	address := models.AddressRequest{
+		Label:      "shipping",
+		Street:     "Avenida Paulista",
+		Number:     "1000",
+		District:   "Bela Vista",
+		City:       "São Paulo",
+		State:      "SP",
+		PostalCode: "01310100",
+		Country:    "br",
+	}
+
+	jsonData, _ := json.Marshal(address)
+	req := httptest.NewRequest("POST", "/api/v1/contacts/1/addresses", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 201, resp.StatusCode)
+
+	var response models.Address
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	assert.NoError(t, err)
+	assert.Equal(t, "01310-100", response.PostalCode)
+	assert.Equal(t, "BR", response.Country)
+
+	// Addresses are rendered on the contact itself
+	req = httptest.NewRequest("GET", "/api/v1/contacts/1", nil)
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+
+	var contactResponse models.ContactResponse
+	err = json.NewDecoder(resp.Body).Decode(&contactResponse)
+	assert.NoError(t, err)
+	assert.Len(t, contactResponse.Addresses, 1)
+
+	address.City = "Campinas"
+	jsonData, _ = json.Marshal(address)
+	req = httptest.NewRequest("PUT", "/api/v1/contacts/1/addresses/1", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err = app.Test(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	req = httptest.NewRequest("DELETE", "/api/v1/contacts/1/addresses/1", nil)
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 204, resp.StatusCode)
+
+	req = httptest.NewRequest("DELETE", "/api/v1/contacts/1/addresses/1", nil)
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 404, resp.StatusCode)
+}
+
+func TestCreateAddressValidation(t *testing.T) {
+	db := setupTestDB()
+	app := setupTestApp(db)
+
+	contact := &models.Contact{Name: "Test User", Email: "test@example.com"}
+	db.Create(contact)
+
+	tests := []struct {
+		name    string
+		address models.AddressRequest
+	}{
+		{"invalid CEP", models.AddressRequest{Street: "Rua A", City: "Recife", PostalCode: "5000-000", Country: "BR"}},
+		{"invalid country", models.AddressRequest{Street: "Rua A", City: "Recife", PostalCode: "50000-000", Country: "XX"}},
+		{"missing street", models.AddressRequest{City: "Recife", PostalCode: "50000-000", Country: "BR"}},
+	}
+
+	for _, tt := range tests {
+		jsonData, _ := json.Marshal(tt.address)
+		req := httptest.NewRequest("POST", "/api/v1/contacts/1/addresses", bytes.NewBuffer(jsonData))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 400, resp.StatusCode, tt.name)
+	}
+}
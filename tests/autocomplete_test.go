@@ -0,0 +1,113 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"api-contacts-go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAutocomplete(t *testing.T) {
+	db := setupTestDB()
+	app := setupTestApp(db)
+
+	contacts := []models.CreateContactRequest{
+		{Name: "Mariana Souza", Email: "mariana@example.com"},
+		{Name: "Ana Maria Lima", Email: "ana@example.com"},
+		{Name: "Márcio Costa", Email: "marcio@example.com"},
+		{Name: "Bruno Alves", Email: "mar.bruno@example.com"},
+		{Name: "Carla Dias", Email: "carla@example.com"},
+	}
+	ids := map[string]uint{}
+	for _, contact := range contacts {
+		jsonData, _ := json.Marshal(contact)
+		req := httptest.NewRequest("POST", "/api/v1/contacts", bytes.NewBuffer(jsonData))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 201, resp.StatusCode)
+
+		var created models.ContactResponse
+		json.NewDecoder(resp.Body).Decode(&created)
+		ids[contact.Name] = created.ID
+	}
+
+	suggest := func(query string) (int, []string) {
+		req := httptest.NewRequest("GET", "/api/v1/contacts/autocomplete?"+query, nil)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+
+		var response models.AutocompleteResponse
+		json.NewDecoder(resp.Body).Decode(&response)
+		names := []string{}
+		for _, suggestion := range response.Data {
+			names = append(names, suggestion.Name)
+		}
+		return resp.StatusCode, names
+	}
+	selectContact := func(id uint) int {
+		req := httptest.NewRequest("POST", fmt.Sprintf("/api/v1/contacts/%d/selections", id), nil)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	// Whole names first, then words of the name and emails, recent first
+	status, names := suggest("prefix=MAR")
+	assert.Equal(t, 200, status)
+	assert.Equal(t, []string{"Márcio Costa", "Mariana Souza", "Bruno Alves", "Ana Maria Lima"}, names)
+
+	// Accents are ignored
+	_, names = suggest("prefix=marci")
+	assert.Equal(t, []string{"Márcio Costa"}, names)
+
+	// Picked contacts move up within their tier
+	assert.Equal(t, 204, selectContact(ids["Mariana Souza"]))
+	_, names = suggest("prefix=mar&limit=2")
+	assert.Equal(t, []string{"Mariana Souza", "Márcio Costa"}, names)
+
+	// Wildcards are taken literally
+	_, names = suggest("prefix=%25")
+	assert.Empty(t, names)
+
+	status, _ = suggest("prefix=")
+	assert.Equal(t, 400, status)
+	assert.Equal(t, 404, selectContact(9999))
+}
+
+func TestAutocompleteRanksBoostedPastCandidates(t *testing.T) {
+	db := setupTestDB()
+	app := setupTestApp(db)
+
+	// The picked contact sorts last and was updated first, so neither the
+	// prefix ranges nor recency reach it among the many other matches
+	picked := models.Contact{Name: "Mazé Zanetti", Email: "maze@example.com", SelectionCount: 3}
+	db.Create(&picked)
+	for i := range 250 {
+		db.Create(&models.Contact{Name: fmt.Sprintf("Maria %03d", i), Email: fmt.Sprintf("maria%03d@example.com", i)})
+	}
+
+	suggest := func(query string) []uint {
+		req := httptest.NewRequest("GET", "/api/v1/contacts/autocomplete?"+query, nil)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var response models.AutocompleteResponse
+		json.NewDecoder(resp.Body).Decode(&response)
+		ids := []uint{}
+		for _, suggestion := range response.Data {
+			ids = append(ids, suggestion.ID)
+		}
+		return ids
+	}
+
+	assert.Equal(t, picked.ID, suggest("prefix=ma&limit=3")[0])
+	assert.Equal(t, picked.ID, suggest("prefix=m&limit=3")[0])
+}
@@ -0,0 +1,104 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"api-contacts-go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContactsShareCompany(t *testing.T) {
+	db := setupTestDB()
+	app := setupTestApp(db)
+
+	contacts := []models.CreateContactRequest{
+		{Name: "João Silva", Email: "joao@example.com", Company: "Tech Corp"},
+		{Name: "Maria Santos", Email: "maria@example.com", Company: "techcorp"},
+		{Name: "Pedro Oliveira", Email: "pedro@example.com", Company: "Design Studio"},
+	}
+	for _, contact := range contacts {
+		jsonData, _ := json.Marshal(contact)
+		req := httptest.NewRequest("POST", "/api/v1/contacts", bytes.NewBuffer(jsonData))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 201, resp.StatusCode)
+
+		var response models.ContactResponse
+		err = json.NewDecoder(resp.Body).Decode(&response)
+		assert.NoError(t, err)
+		assert.NotNil(t, response.CompanyID)
+	}
+
+	req := httptest.NewRequest("GET", "/api/v1/companies", nil)
+	resp, err := app.Test(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var companies models.PaginatedCompanyResponse
+	err = json.NewDecoder(resp.Body).Decode(&companies)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), companies.Total)
+	assert.Equal(t, "Tech Corp", companies.Data[1].Name)
+	assert.Equal(t, int64(2), companies.Data[1].ContactCount)
+
+	req = httptest.NewRequest("GET", "/api/v1/companies/1/contacts", nil)
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+
+	var companyContacts models.PaginatedResponse
+	err = json.NewDecoder(resp.Body).Decode(&companyContacts)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), companyContacts.Total)
+	assert.Equal(t, "Tech Corp", companyContacts.Data[0].Company)
+
+	// Renaming the company renames it on its contacts
+	name := "Tech Corporation"
+	jsonData, _ := json.Marshal(models.UpdateCompanyRequest{Name: &name})
+	req = httptest.NewRequest("PUT", "/api/v1/companies/1", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	req = httptest.NewRequest("GET", "/api/v1/contacts/2", nil)
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+
+	var contact models.ContactResponse
+	err = json.NewDecoder(resp.Body).Decode(&contact)
+	assert.NoError(t, err)
+	assert.Equal(t, "Tech Corporation", contact.Company)
+
+	// Spelling variants of an existing company are rejected
+	jsonData, _ = json.Marshal(models.CreateCompanyRequest{Name: "design-studio"})
+	req = httptest.NewRequest("POST", "/api/v1/companies", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 409, resp.StatusCode)
+
+	// Deleting the company unlinks its contacts
+	req = httptest.NewRequest("DELETE", "/api/v1/companies/1", nil)
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 204, resp.StatusCode)
+
+	req = httptest.NewRequest("GET", "/api/v1/contacts/1", nil)
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+
+	var unlinked models.ContactResponse
+	err = json.NewDecoder(resp.Body).Decode(&unlinked)
+	assert.NoError(t, err)
+	assert.Nil(t, unlinked.CompanyID)
+}
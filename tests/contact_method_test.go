@@ -0,0 +1,148 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"api-contacts-go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateContactWithEmailsAndPhones(t *testing.T) {
+	db := setupTestDB()
+	app := setupTestApp(db)
+
+	contact := models.CreateContactRequest{
+		Name: "Test User",
+		Emails: []models.ContactEmailInput{
+			{Email: "test@home.com", Label: "home"},
+			{Email: "test@work.com", Label: "work", Primary: true},
+		},
+		Phones: []models.ContactPhoneInput{
+			{Number: "+55 11 99999-9999", Label: "mobile"},
+		},
+	}
+
+	jsonData, _ := json.Marshal(contact)
+	req := httptest.NewRequest("POST", "/api/v1/contacts", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 201, resp.StatusCode)
+
+	var response models.ContactResponse
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	assert.NoError(t, err)
+	assert.Equal(t, "test@work.com", response.Email)
+	assert.Equal(t, "+55 11 99999-9999", response.Phone)
+	assert.Len(t, response.Emails, 2)
+	assert.Len(t, response.Phones, 1)
+	assert.Equal(t, "mobile", response.Phones[0].Label)
+
+	// Secondary addresses are reserved as well
+	duplicate := models.CreateContactRequest{Name: "Other User", Email: "TEST@home.com"}
+	jsonData, _ = json.Marshal(duplicate)
+	req = httptest.NewRequest("POST", "/api/v1/contacts", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err = app.Test(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 409, resp.StatusCode)
+}
+
+func TestSearchCoversEveryEmail(t *testing.T) {
+	db := setupSearchDB(t)
+	app := setupTestApp(db)
+
+	contact := models.CreateContactRequest{
+		Name: "Test User",
+		Emails: []models.ContactEmailInput{
+			{Email: "test@home.com", Label: "home"},
+			{Email: "test@work.com", Label: "work", Primary: true},
+		},
+	}
+
+	jsonData, _ := json.Marshal(contact)
+	req := httptest.NewRequest("POST", "/api/v1/contacts", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 201, resp.StatusCode)
+
+	search := func(query string) int64 {
+		req := httptest.NewRequest("GET", "/api/v1/contacts/search?q="+query, nil)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+
+		var searchResponse models.PaginatedResponse
+		err = json.NewDecoder(resp.Body).Decode(&searchResponse)
+		assert.NoError(t, err)
+		return searchResponse.Total
+	}
+	assert.Equal(t, int64(1), search("home.com"))
+	assert.Equal(t, int64(1), search("test@home.com"))
+
+	// The search vector follows changes to the secondary addresses
+	emails := []models.ContactEmailInput{{Email: "test@work.com", Label: "work", Primary: true}}
+	jsonData, _ = json.Marshal(models.UpdateContactRequest{Emails: &emails})
+	req = httptest.NewRequest("PUT", "/api/v1/contacts/1", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, int64(0), search("home.com"))
+	assert.Equal(t, int64(1), search("work.com"))
+}
+
+func TestUpdateContactEmails(t *testing.T) {
+	db := setupTestDB()
+	app := setupTestApp(db)
+
+	contact := &models.Contact{Name: "Test User", Email: "test@example.com"}
+	db.Create(contact)
+
+	updateData := models.UpdateContactRequest{
+		Emails: &[]models.ContactEmailInput{
+			{Email: "test@example.com", Label: "other"},
+			{Email: "new@example.com", Label: "work", Primary: true},
+		},
+	}
+
+	jsonData, _ := json.Marshal(updateData)
+	req := httptest.NewRequest("PUT", "/api/v1/contacts/1", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var response models.ContactResponse
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	assert.NoError(t, err)
+	assert.Equal(t, "new@example.com", response.Email)
+	assert.Len(t, response.Emails, 2)
+	assert.True(t, response.Emails[0].Primary)
+
+	// Two primary entries are rejected
+	updateData.Emails = &[]models.ContactEmailInput{
+		{Email: "a@example.com", Primary: true},
+		{Email: "b@example.com", Primary: true},
+	}
+	jsonData, _ = json.Marshal(updateData)
+	req = httptest.NewRequest("PUT", "/api/v1/contacts/1", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err = app.Test(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 400, resp.StatusCode)
+}
@@ -3,12 +3,9 @@ package tests
 import (
 	"bytes"
 	"encoding/json"
-	"net/http"
 	"net/http/httptest"
 	"testing"
 
-	"api-contacts-go/internal/config"
-	"api-contacts-go/internal/database"
 	"api-contacts-go/internal/handlers"
 	"api-contacts-go/internal/models"
 
@@ -25,7 +22,7 @@ func setupTestDB() *gorm.DB {
 	}
 
 	// Auto migrate
-	db.AutoMigrate(&models.Contact{})
+	db.AutoMigrate(&models.Contact{}, &models.Tag{}, &models.ContactEmail{}, &models.ContactPhone{}, &models.Address{}, &models.CustomFieldDefinition{}, &models.Note{}, &models.Company{}, &models.Relationship{}, &models.ContactList{}, &models.SmartList{}, &models.SignificantDate{}, &models.ContactNameWord{}, &models.SearchIndexUpdate{})
 
 	return db
 }
@@ -33,7 +30,7 @@ func setupTestDB() *gorm.DB {
 func setupTestApp(db *gorm.DB) *fiber.App {
 	app := fiber.New()
 	api := app.Group("/api/v1")
-	handlers.SetupRoutes(api, db)
+	handlers.SetupRoutes(api, db, nil)
 	return app
 }
 
@@ -186,7 +183,7 @@ func TestDeleteContact(t *testing.T) {
 }
 
 func TestSearchContacts(t *testing.T) {
-	db := setupTestDB()
+	db := setupSearchDB(t)
 	app := setupTestApp(db)
 
 	// Create test contacts
@@ -214,6 +211,81 @@ func TestSearchContacts(t *testing.T) {
 	assert.Equal(t, "João Silva", response.Data[0].Name)
 }
 
+func TestSearchContactsIgnoresAccents(t *testing.T) {
+	db := setupSearchDB(t)
+	app := setupTestApp(db)
+
+	// Create test contacts
+	contacts := []models.Contact{
+		{Name: "João Silva", Email: "joao@example.com"},
+		{Name: "Maria Conceição", Email: "maria@example.com"},
+		{Name: "Pedro Oliveira", Email: "pedro@example.com"},
+	}
+
+	for _, contact := range contacts {
+		db.Create(&contact)
+	}
+
+	req := httptest.NewRequest("GET", "/api/v1/contacts/search?q=Joao", nil)
+	resp, err := app.Test(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var response models.PaginatedResponse
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), response.Total)
+	assert.Len(t, response.Data, 1)
+	assert.Equal(t, "João Silva", response.Data[0].Name)
+
+	req = httptest.NewRequest("GET", "/api/v1/contacts/search?q=conceicao", nil)
+	resp, err = app.Test(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	response = models.PaginatedResponse{}
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), response.Total)
+	assert.Equal(t, "Maria Conceição", response.Data[0].Name)
+}
+
+func TestCompaniesSortIgnoresAccents(t *testing.T) {
+	db := setupTestDB()
+	app := setupTestApp(db)
+
+	contacts := []models.CreateContactRequest{
+		{Name: "João Silva", Email: "joao@example.com", Company: "Tech Corp"},
+		{Name: "Pedro Oliveira", Email: "pedro@example.com", Company: "Ética Design"},
+	}
+	for _, contact := range contacts {
+		jsonData, _ := json.Marshal(contact)
+		req := httptest.NewRequest("POST", "/api/v1/contacts", bytes.NewBuffer(jsonData))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 201, resp.StatusCode)
+	}
+
+	req := httptest.NewRequest("GET", "/api/v1/companies", nil)
+	resp, err := app.Test(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	// Accents do not affect the alphabetical order
+	var companies models.PaginatedCompanyResponse
+	err = json.NewDecoder(resp.Body).Decode(&companies)
+	assert.NoError(t, err)
+	if assert.Len(t, companies.Data, 2) {
+		assert.Equal(t, "Ética Design", companies.Data[0].Name)
+		assert.Equal(t, "Tech Corp", companies.Data[1].Name)
+	}
+}
+
 func stringPtr(s string) *string {
 	return &s
 }
@@ -0,0 +1,95 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"api-contacts-go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursorPagination(t *testing.T) {
+	db := setupTestDB()
+	app := setupTestApp(db)
+
+	// Pairs of contacts share a creation time to exercise the id tie-break
+	created := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 7; i++ {
+		db.Create(&models.Contact{
+			Name:      fmt.Sprintf("Contato %c", 'G'-i),
+			Email:     fmt.Sprintf("contato%d@example.com", i),
+			CreatedAt: created.AddDate(0, 0, i/2),
+		})
+	}
+
+	page := func(query string) (int, models.CursorPaginatedResponse) {
+		req := httptest.NewRequest("GET", "/api/v1/contacts?"+query, nil)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+
+		var response models.CursorPaginatedResponse
+		json.NewDecoder(resp.Body).Decode(&response)
+		return resp.StatusCode, response
+	}
+	ids := func(response models.CursorPaginatedResponse) []uint {
+		ids := []uint{}
+		for _, contact := range response.Data {
+			ids = append(ids, contact.ID)
+		}
+		return ids
+	}
+
+	for _, tt := range []struct {
+		sort string
+		ids  []uint
+	}{
+		{"", []uint{7, 6, 5, 4, 3, 2, 1}},
+		{"&sort=name", []uint{7, 6, 5, 4, 3, 2, 1}},
+		{"&sort=created_at", []uint{1, 2, 3, 4, 5, 6, 7}},
+		{"&sort=created_at,-id", []uint{2, 1, 4, 3, 6, 5, 7}},
+	} {
+		// Forwards through every page
+		var seen []uint
+		var pages []models.CursorPaginatedResponse
+		status, response := page("limit=3&cursor=" + tt.sort)
+		for {
+			assert.Equal(t, 200, status)
+			seen = append(seen, ids(response)...)
+			pages = append(pages, response)
+			if response.NextCursor == nil {
+				break
+			}
+			status, response = page("limit=3&cursor=" + url.QueryEscape(*response.NextCursor) + tt.sort)
+		}
+		assert.Equal(t, tt.ids, seen, tt.sort)
+		assert.Len(t, pages, 3)
+		assert.Nil(t, pages[0].PrevCursor)
+
+		// And back again
+		status, response = page("limit=3&cursor=" + url.QueryEscape(*pages[2].PrevCursor) + tt.sort)
+		assert.Equal(t, 200, status)
+		assert.Equal(t, ids(pages[1]), ids(response), tt.sort)
+		status, response = page("limit=3&cursor=" + url.QueryEscape(*response.PrevCursor) + tt.sort)
+		assert.Equal(t, 200, status)
+		assert.Equal(t, ids(pages[0]), ids(response), tt.sort)
+		assert.Nil(t, response.PrevCursor)
+		assert.NotNil(t, response.NextCursor)
+	}
+
+	// Contacts added between page loads do not shift the next page
+	_, first := page("limit=3&cursor=")
+	db.Create(&models.Contact{Name: "Contato Novo", Email: "novo@example.com", CreatedAt: created.AddDate(0, 1, 0)})
+	_, second := page("limit=3&cursor=" + url.QueryEscape(*first.NextCursor))
+	assert.Equal(t, []uint{4, 3, 2}, ids(second))
+
+	// Cursors are tied to their sort
+	status, _ := page("limit=3&sort=name&cursor=" + url.QueryEscape(*first.NextCursor))
+	assert.Equal(t, 400, status)
+	status, _ = page("cursor=not-a-cursor")
+	assert.Equal(t, 400, status)
+}
@@ -0,0 +1,145 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"api-contacts-go/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func createCustomField(t *testing.T, app *fiber.App, field models.CreateCustomFieldRequest) {
+	jsonData, _ := json.Marshal(field)
+	req := httptest.NewRequest("POST", "/api/v1/custom-fields", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 201, resp.StatusCode)
+}
+
+func TestCustomFieldValidation(t *testing.T) {
+	db := setupTestDB()
+	app := setupTestApp(db)
+
+	createCustomField(t, app, models.CreateCustomFieldRequest{Name: "contract_number", Type: "string", Required: true})
+	createCustomField(t, app, models.CreateCustomFieldRequest{Name: "plan", Type: "enum", EnumValues: []string{"free", "gold"}})
+
+	tests := []struct {
+		name         string
+		customFields map[string]any
+		status       int
+	}{
+		{"valid values", map[string]any{"contract_number": "C-1", "plan": "gold"}, 201},
+		{"missing required field", map[string]any{"plan": "gold"}, 400},
+		{"unknown enum value", map[string]any{"contract_number": "C-2", "plan": "silver"}, 400},
+		{"wrong type", map[string]any{"contract_number": 42}, 400},
+		{"undefined field", map[string]any{"contract_number": "C-3", "color": "blue"}, 400},
+	}
+
+	for i, tt := range tests {
+		contact := models.CreateContactRequest{
+			Name:         "Test User",
+			Email:        "test" + string(rune('a'+i)) + "@example.com",
+			CustomFields: tt.customFields,
+		}
+
+		jsonData, _ := json.Marshal(contact)
+		req := httptest.NewRequest("POST", "/api/v1/contacts", bytes.NewBuffer(jsonData))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, tt.status, resp.StatusCode, tt.name)
+	}
+
+	// Duplicate definitions are rejected
+	jsonData, _ := json.Marshal(models.CreateCustomFieldRequest{Name: "plan", Type: "string"})
+	req := httptest.NewRequest("POST", "/api/v1/custom-fields", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 409, resp.StatusCode)
+}
+
+func TestFilterContactsByCustomField(t *testing.T) {
+	db := setupTestDB()
+	app := setupTestApp(db)
+
+	createCustomField(t, app, models.CreateCustomFieldRequest{Name: "nps", Type: "number"})
+	createCustomField(t, app, models.CreateCustomFieldRequest{Name: "active", Type: "bool"})
+
+	contacts := []models.Contact{
+		{Name: "João Silva", Email: "joao@example.com", CustomFields: models.JSONMap{"nps": 9.0, "active": true}},
+		{Name: "Maria Santos", Email: "maria@example.com", CustomFields: models.JSONMap{"nps": 6.0, "active": true}},
+		{Name: "Pedro Oliveira", Email: "pedro@example.com"},
+	}
+	for _, contact := range contacts {
+		db.Create(&contact)
+	}
+
+	tests := []struct {
+		url    string
+		status int
+		total  int64
+	}{
+		{"/api/v1/contacts?cf.nps=9", 200, 1},
+		{"/api/v1/contacts?cf.active=true", 200, 2},
+		{"/api/v1/contacts/search?q=maria&mode=phonetic&cf.active=true", 200, 1},
+		{"/api/v1/contacts?cf.nps=high", 400, 0},
+		{"/api/v1/contacts?cf.unknown=1", 400, 0},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", tt.url, nil)
+		resp, err := app.Test(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, tt.status, resp.StatusCode, tt.url)
+
+		if tt.status == 200 {
+			var response models.PaginatedResponse
+			err = json.NewDecoder(resp.Body).Decode(&response)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.total, response.Total, tt.url)
+		}
+	}
+
+	// Null clears a value on update
+	updateData := models.UpdateContactRequest{CustomFields: map[string]any{"nps": nil}}
+	jsonData, _ := json.Marshal(updateData)
+	req := httptest.NewRequest("PUT", "/api/v1/contacts/1", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var response models.ContactResponse
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"active": true}, response.CustomFields)
+
+	// Deleting a definition drops the stored values
+	req = httptest.NewRequest("DELETE", "/api/v1/custom-fields/2", nil)
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 204, resp.StatusCode)
+
+	req = httptest.NewRequest("GET", "/api/v1/contacts/2", nil)
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+
+	var deletedResponse models.ContactResponse
+	err = json.NewDecoder(resp.Body).Decode(&deletedResponse)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"nps": 6.0}, deletedResponse.CustomFields)
+}
@@ -0,0 +1,95 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"api-contacts-go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpcomingDates(t *testing.T) {
+	db := setupTestDB()
+	app := setupTestApp(db)
+
+	soon := time.Now().AddDate(0, 0, 3)
+	later := time.Now().AddDate(0, 0, 40)
+	wedding := time.Now().AddDate(0, 0, 10)
+
+	for _, contact := range []models.CreateContactRequest{
+		{Name: "Soon Birthday", Email: "soon@example.com", Birthday: soon.Format("--01-02")},
+		{Name: "Later Birthday", Email: "later@example.com", Birthday: later.Format("2006-01-02")},
+	} {
+		jsonData, _ := json.Marshal(contact)
+		req := httptest.NewRequest("POST", "/api/v1/contacts", bytes.NewBuffer(jsonData))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 201, resp.StatusCode)
+	}
+
+	jsonData, _ := json.Marshal(models.SignificantDateRequest{Label: "wedding", Date: wedding.AddDate(-4, 0, 0).Format("2006-01-02")})
+	req := httptest.NewRequest("POST", "/api/v1/contacts/1/dates", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 201, resp.StatusCode)
+
+	req = httptest.NewRequest("GET", "/api/v1/contacts/upcoming-dates?days=30", nil)
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var upcoming []models.UpcomingDate
+	err = json.NewDecoder(resp.Body).Decode(&upcoming)
+	assert.NoError(t, err)
+	if assert.Len(t, upcoming, 2) {
+		assert.Equal(t, models.DateKindBirthday, upcoming[0].Kind)
+		assert.Equal(t, 3, upcoming[0].DaysUntil)
+		assert.Nil(t, upcoming[0].Years)
+		assert.Equal(t, "wedding", upcoming[1].Label)
+		assert.Equal(t, 4, *upcoming[1].Years)
+	}
+
+	req = httptest.NewRequest("GET", "/api/v1/contacts/upcoming-dates?days=0", nil)
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 400, resp.StatusCode)
+
+	req = httptest.NewRequest("GET", "/api/v1/contacts/calendar.ics", nil)
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	body, _ := io.ReadAll(resp.Body)
+	assert.True(t, strings.HasPrefix(string(body), "BEGIN:VCALENDAR\r\n"))
+	assert.Equal(t, 3, strings.Count(string(body), "RRULE:FREQ=YEARLY"))
+}
+
+func TestPartialDateNextOccurrence(t *testing.T) {
+	leapDay, err := models.ParsePartialDate("--02-29")
+	assert.NoError(t, err)
+
+	// February 29 falls on the 28th in common years
+	from := time.Date(2025, 1, 10, 15, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC), leapDay.NextOccurrence(from))
+	from = time.Date(2028, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC), leapDay.NextOccurrence(from))
+
+	// Dates already past this year wrap into the next one
+	newYear, err := models.ParsePartialDate("1990-01-05")
+	assert.NoError(t, err)
+	from = time.Date(2027, 12, 20, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2028, 1, 5, 0, 0, 0, 0, time.UTC), newYear.NextOccurrence(from))
+
+	_, err = models.ParsePartialDate("2023-02-29")
+	assert.Error(t, err)
+}
@@ -0,0 +1,126 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"api-contacts-go/internal/handlers"
+	"api-contacts-go/internal/models"
+	"api-contacts-go/internal/services"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchFacets(t *testing.T) {
+	db := setupSearchDB(t)
+	app := setupTestApp(db)
+
+	contacts := []models.CreateContactRequest{
+		{Name: "Ana Silva", Email: "ana@techcorp.com", Company: "Tech Corp"},
+		{Name: "Bruno Silva", Email: "bruno@techcorp.com", Company: "Tech Corp"},
+		{Name: "Carla Silva", Email: "carla@acme.com", Company: "Acme", Emails: []models.ContactEmailInput{
+			{Email: "carla@gmail.com", Label: models.LabelHome},
+		}},
+		{Name: "Diego Silva", Email: "diego@GMAIL.com"},
+		{Name: "Eva Santos", Email: "eva@acme.com", Company: "Acme"},
+	}
+	for _, contact := range contacts {
+		jsonData, _ := json.Marshal(contact)
+		req := httptest.NewRequest("POST", "/api/v1/contacts", bytes.NewBuffer(jsonData))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 201, resp.StatusCode)
+	}
+
+	search := func(query string) (int, models.PaginatedResponse) {
+		req := httptest.NewRequest("GET", "/api/v1/contacts/search?"+query, nil)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+
+		var response models.PaginatedResponse
+		json.NewDecoder(resp.Body).Decode(&response)
+		return resp.StatusCode, response
+	}
+
+	// Counts cover every match, not only the page
+	for _, mode := range []string{"text", "fuzzy&threshold=0.6"} {
+		status, response := search("q=silva&limit=1&facets=company,email_domain&mode=" + mode)
+		assert.Equal(t, 200, status, mode)
+		assert.Len(t, response.Data, 1, mode)
+		assert.Equal(t, int64(4), response.Total, mode)
+		assert.Equal(t, []models.FacetBucket{
+			{Value: "Tech Corp", Count: 2},
+			{Value: "Acme", Count: 1},
+		}, response.Facets["company"], mode)
+		assert.Equal(t, []models.FacetBucket{
+			{Value: "gmail.com", Count: 2},
+			{Value: "techcorp.com", Count: 2},
+			{Value: "acme.com", Count: 1},
+		}, response.Facets["email_domain"], mode)
+	}
+
+	// Facets follow the filters and can be limited
+	status, response := search("q=silva&facets=email_domain&facet_size=1&company=acme")
+	assert.Equal(t, 200, status)
+	assert.Equal(t, []models.FacetBucket{{Value: "acme.com", Count: 1}}, response.Facets["email_domain"])
+	assert.NotContains(t, response.Facets, "company")
+
+	status, response = search("q=silva")
+	assert.Equal(t, 200, status)
+	assert.Nil(t, response.Facets)
+
+	status, _ = search("q=silva&facets=tags")
+	assert.Equal(t, 400, status)
+
+	// Company spellings differing in case or accents are one bucket
+	db.Create(&models.Contact{Name: "Fábio Silva", Email: "fabio@techcorp.com", Company: "tech corp"})
+	db.Create(&models.Contact{Name: "Gil Silva", Email: "gil@techcorp.com", Company: "Téch Corp"})
+	_, response = search("q=silva&facets=company")
+	assert.Equal(t, []models.FacetBucket{
+		{Value: "Tech Corp", Count: 4},
+		{Value: "Acme", Count: 1},
+	}, response.Facets["company"])
+}
+
+func TestIndexedCompanyFacetGroupsSpellings(t *testing.T) {
+	db := setupTestDB()
+	index, err := services.OpenDiskIndex(filepath.Join(t.TempDir(), "search.idx"))
+	assert.NoError(t, err)
+	t.Cleanup(func() { index.Close() })
+	app := fiber.New()
+	handlers.SetupRoutes(app.Group("/api/v1"), db, index)
+
+	contacts := []models.Contact{
+		{Name: "Ana Silva", Email: "ana@techcorp.com", Company: "tech corp"},
+		{Name: "Bruno Silva", Email: "bruno@techcorp.com", Company: "Tech Corp"},
+		{Name: "Carla Silva", Email: "carla@techcorp.com", Company: "Téch Corp"},
+		{Name: "Diego Silva", Email: "diego@acme.com", Company: "Acme"},
+		{Name: "Eva Silva", Email: "eva@acme.com", Company: "ACME"},
+		{Name: "Fábio Silva", Email: "fabio@globex.com", Company: "Globex"},
+	}
+	for _, contact := range contacts {
+		db.Create(&contact)
+	}
+	_, err = services.NewContactService(db, index).Reindex()
+	assert.NoError(t, err)
+
+	// Indexed matches are counted in Go, still grouped by the folded name
+	req := httptest.NewRequest("GET", "/api/v1/contacts/search?q=silva&facets=company", nil)
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var response models.PaginatedResponse
+	json.NewDecoder(resp.Body).Decode(&response)
+	assert.Equal(t, []models.FacetBucket{
+		{Value: "Tech Corp", Count: 3},
+		{Value: "ACME", Count: 2},
+		{Value: "Globex", Count: 1},
+	}, response.Facets["company"])
+}
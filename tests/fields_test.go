@@ -0,0 +1,106 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"testing"
+
+	"api-contacts-go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSparseFieldsets(t *testing.T) {
+	db := setupTestDB()
+	app := setupTestApp(db)
+
+	contacts := []models.CreateContactRequest{
+		{Name: "João Silva", Email: "joao@example.com", Phone: "+55 11 99999-1111"},
+		{Name: "Maria Santos", Email: "maria@example.com", Company: "Tech Corp"},
+		{Name: "Pedro Oliveira", Email: "pedro@example.com"},
+	}
+	for _, contact := range contacts {
+		jsonData, _ := json.Marshal(contact)
+		req := httptest.NewRequest("POST", "/api/v1/contacts", bytes.NewBuffer(jsonData))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 201, resp.StatusCode)
+	}
+
+	jsonData, _ := json.Marshal(models.CreateRelationshipRequest{RelatedContactID: 2, Type: models.RelationshipManager})
+	req := httptest.NewRequest("POST", "/api/v1/contacts/1/relationships", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 201, resp.StatusCode)
+
+	list := func(path string) (int, map[string]any) {
+		req := httptest.NewRequest("GET", path, nil)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+
+		var body map[string]any
+		json.NewDecoder(resp.Body).Decode(&body)
+		return resp.StatusCode, body
+	}
+	keys := func(body map[string]any) []string {
+		data := body["data"].([]any)
+		keys := []string{}
+		for key := range data[0].(map[string]any) {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		return keys
+	}
+
+	status, body := list("/api/v1/contacts?fields=id,name&sort=name")
+	assert.Equal(t, 200, status)
+	assert.Equal(t, []string{"id", "name"}, keys(body))
+	assert.Equal(t, "João Silva", body["data"].([]any)[0].(map[string]any)["name"])
+
+	status, body = list("/api/v1/contacts?fields=name,phones&include=relationships&sort=id")
+	assert.Equal(t, 200, status)
+	assert.Equal(t, []string{"name", "phones", "relationships"}, keys(body))
+	first := body["data"].([]any)[0].(map[string]any)
+	assert.Len(t, first["phones"], 1)
+	if assert.Len(t, first["relationships"], 1) {
+		relationship := first["relationships"].([]any)[0].(map[string]any)
+		assert.Equal(t, models.RelationshipManager, relationship["type"])
+	}
+
+	// Including without choosing fields keeps the full response
+	status, body = list("/api/v1/contacts?include=relationships")
+	assert.Equal(t, 200, status)
+	assert.Contains(t, keys(body), "relationships")
+	assert.Contains(t, keys(body), "custom_fields")
+
+	status, body = list("/api/v1/contacts")
+	assert.Equal(t, 200, status)
+	assert.NotContains(t, keys(body), "relationships")
+
+	status, body = list("/api/v1/contacts/search?q=santos&mode=phonetic&fields=name,company,score")
+	assert.Equal(t, 200, status)
+	assert.Equal(t, []string{"company", "name", "score"}, keys(body))
+
+	// Cursors still work when the sort keys are not rendered
+	status, body = list("/api/v1/contacts?fields=id&sort=company,-name&limit=2&cursor=")
+	assert.Equal(t, 200, status)
+	assert.Equal(t, []string{"id"}, keys(body))
+	status, body = list("/api/v1/contacts?fields=id&sort=company,-name&limit=2&cursor=" + url.QueryEscape(body["next_cursor"].(string)))
+	assert.Equal(t, 200, status)
+	assert.Equal(t, float64(2), body["data"].([]any)[0].(map[string]any)["id"])
+
+	for _, path := range []string{
+		"/api/v1/contacts?fields=id,salary",
+		"/api/v1/contacts?include=notes",
+		"/api/v1/contacts/search?q=santos&fields=nome",
+	} {
+		status, _ := list(path)
+		assert.Equal(t, 400, status, path)
+	}
+}
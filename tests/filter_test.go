@@ -0,0 +1,240 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"api-contacts-go/internal/filter"
+	"api-contacts-go/internal/models"
+	"api-contacts-go/internal/services"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContactFieldFilters(t *testing.T) {
+	db := setupTestDB()
+	app := setupTestApp(db)
+
+	contacts := []models.CreateContactRequest{
+		{Name: "João Silva", Email: "joao@techcorp.com", Phone: "+55 11 99999-1111", Company: "Tech Corp"},
+		{Name: "Maria Santos", Email: "maria@gmail.com", Company: "Tech Corp", Emails: []models.ContactEmailInput{
+			{Email: "maria@acme.com", Label: models.LabelWork},
+		}},
+		{Name: "Pedro Oliveira", Email: "pedro@acme.com", Phone: "+55 21 98888-2222", Company: "Acme"},
+	}
+	for i, contact := range contacts {
+		jsonData, _ := json.Marshal(contact)
+		req := httptest.NewRequest("POST", "/api/v1/contacts", bytes.NewBuffer(jsonData))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 201, resp.StatusCode)
+
+		// One contact per month of 2025
+		created := time.Date(2025, time.Month(i+1), 15, 12, 0, 0, 0, time.UTC)
+		db.Model(&models.Contact{}).Where("id = ?", i+1).UpdateColumns(map[string]any{"created_at": created, "updated_at": created})
+	}
+
+	list := func(path string) (int, models.PaginatedResponse) {
+		req := httptest.NewRequest("GET", path, nil)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+
+		var response models.PaginatedResponse
+		json.NewDecoder(resp.Body).Decode(&response)
+		return resp.StatusCode, response
+	}
+	names := func(response models.PaginatedResponse) []string {
+		names := []string{}
+		for _, contact := range response.Data {
+			names = append(names, contact.Name)
+		}
+		return names
+	}
+
+	tests := []struct {
+		query string
+		names []string
+	}{
+		{"company=tech%20corp", []string{"Maria Santos", "João Silva"}},
+		{"email_domain=acme.com", []string{"Pedro Oliveira", "Maria Santos"}},
+		{"email_domain=@ACME.com&has_phone=true", []string{"Pedro Oliveira"}},
+		{"has_phone=false", []string{"Maria Santos"}},
+		{"ids=1,3", []string{"Pedro Oliveira", "João Silva"}},
+		{"created_after=2025-01-15", []string{"Pedro Oliveira", "Maria Santos"}},
+		{"created_after=2025-01-14&created_before=2025-03-01", []string{"Maria Santos", "João Silva"}},
+		{"updated_before=2025-02-15T12:00:00Z", []string{"João Silva"}},
+		{"company=Tech%20Corp&email_domain=acme.com", []string{"Maria Santos"}},
+	}
+	for _, tt := range tests {
+		status, response := list("/api/v1/contacts?" + tt.query)
+		assert.Equal(t, 200, status, tt.query)
+		assert.Equal(t, tt.names, names(response), tt.query)
+	}
+
+	// The filters narrow searches too
+	status, response := list("/api/v1/contacts/search?q=silva&mode=phonetic&has_phone=false")
+	assert.Equal(t, 200, status)
+	assert.Equal(t, int64(0), response.Total)
+
+	for _, query := range []string{
+		"ids=1,abc",
+		"ids=0",
+		"email_domain=acme",
+		"email_domain=a%25b.com",
+		"has_phone=maybe",
+		"created_after=15/01/2025",
+		"updated_after=2025-03-01&updated_before=2025-02-01",
+	} {
+		status, _ := list("/api/v1/contacts?" + query)
+		assert.Equal(t, 400, status, query)
+	}
+}
+
+func TestFilterExpression(t *testing.T) {
+	db := setupTestDB()
+	app := setupTestApp(db)
+
+	acme := uint(7)
+	contacts := []models.Contact{
+		{Name: "João Silva", Email: "joao@techcorp.com", Company: "Tech Corp"},
+		{Name: "Maria Santos", Email: "maria@acme.com", Company: "Acme", CompanyID: &acme},
+		{Name: "Pedro Oliveira", Email: "pedro@example.com", Company: "Tech Corp"},
+		{Name: "Ana 100%", Email: "ana@example.com", Company: "Ética"},
+	}
+	for i, contact := range contacts {
+		created := time.Date(2025, time.Month(i+1), 15, 12, 0, 0, 0, time.UTC)
+		contact.CreatedAt = created
+		contact.UpdatedAt = created
+		db.Create(&contact)
+	}
+	db.Exec("UPDATE contacts SET phone = NULL WHERE id = 4")
+
+	list := func(expression string) (int, map[string]any, []string) {
+		req := httptest.NewRequest("GET", "/api/v1/contacts?filter="+url.QueryEscape(expression), nil)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+
+		var body map[string]any
+		json.NewDecoder(resp.Body).Decode(&body)
+
+		names := []string{}
+		if data, ok := body["data"].([]any); ok {
+			for _, contact := range data {
+				names = append(names, contact.(map[string]any)["name"].(string))
+			}
+		}
+		return resp.StatusCode, body, names
+	}
+
+	tests := []struct {
+		expression string
+		names      []string
+	}{
+		{"(company = 'Tech Corp' or email ends with '@acme.com') and created_at > 2025-01-15", []string{"Pedro Oliveira", "Maria Santos"}},
+		{"company = 'tech corp' and not name contains silva", []string{"Pedro Oliveira"}},
+		{"company = etica", []string{"Ana 100%"}},
+		{"name contains '0%'", []string{"Ana 100%"}},
+		{"name contains '_'", []string{}},
+		{"id in (1, 3) or name starts with 'MAR'", []string{"Pedro Oliveira", "Maria Santos", "João Silva"}},
+		{"created_at = 2025-02-15", []string{"Maria Santos"}},
+		{"created_at <= 2025-02-15 AND created_at != 2025-01-15", []string{"Maria Santos"}},
+		{"updated_at < '2025-03-15T12:00:00Z' and id >= 2", []string{"Maria Santos"}},
+
+		// Contacts without a value differ from every value
+		{"company_id != 7", []string{"Ana 100%", "Pedro Oliveira", "João Silva"}},
+		{"not company_id = 7", []string{"Ana 100%", "Pedro Oliveira", "João Silva"}},
+		{"not (company_id in (7, 8) or name contains silva)", []string{"Ana 100%", "Pedro Oliveira"}},
+		{"not company_id != 7", []string{"Maria Santos"}},
+		{"phone != '123' and id >= 3", []string{"Ana 100%", "Pedro Oliveira"}},
+	}
+	for _, tt := range tests {
+		status, _, names := list(tt.expression)
+		assert.Equal(t, 200, status, tt.expression)
+		assert.Equal(t, tt.names, names, tt.expression)
+	}
+
+	// Errors point at the offending character
+	errors := []struct {
+		expression string
+		position   float64
+	}{
+		{"company = 'Tech Corp' and", 26},
+		{"salary > 10", 1},
+		{"name > 'a'", 6},
+		{"id = abc", 6},
+		{"created_at > 2025-13-01", 14},
+		{"name = 'unterminated", 8},
+		{"(name = a", 10},
+		{"name = a; drop table contacts", 9},
+	}
+	for _, tt := range errors {
+		status, body, _ := list(tt.expression)
+		assert.Equal(t, 400, status, tt.expression)
+		assert.Equal(t, tt.position, body["position"], tt.expression)
+		assert.Contains(t, body["error"], "Invalid filter", tt.expression)
+	}
+}
+
+func TestFilterExpressionString(t *testing.T) {
+	expr, err := filter.Parse("not (a = 1 or b contains 'it''s') and c in (x, \"y\")", map[string]filter.FieldType{
+		"a": filter.FieldNumber,
+		"b": filter.FieldText,
+		"c": filter.FieldText,
+	})
+	assert.Nil(t, expr)
+	if assert.Error(t, err) {
+		assert.Equal(t, 30, err.(*filter.Error).Position)
+	}
+
+	expr, err = filter.Parse(`not (id = 1 or name contains 'it\'s') and email in (x, "y")`, services.FilterFields)
+	if assert.NoError(t, err) {
+		assert.Equal(t, `not (id = '1' or name contains 'it\'s') and email in ('x', 'y')`, expr.String())
+	}
+}
+
+// FuzzFilterParse checks that any input either fails with a position inside
+// it or parses into a tree that renders back to itself
+func FuzzFilterParse(f *testing.F) {
+	seeds := []string{
+		"(company = 'Tech Corp' or email ends with '@acme.com') and created_at > 2025-01-01",
+		"not name contains silva",
+		"id in (1, 2, 3)",
+		"updated_at >= '2025-01-01T10:00:00Z'",
+		`name = "a\\"b"`,
+		"((((id = 1))))",
+		"",
+		"name = 'x' or",
+	}
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		expr, err := filter.Parse(input, services.FilterFields)
+		if err != nil {
+			exprErr, ok := err.(*filter.Error)
+			if !ok {
+				t.Fatalf("unexpected error type %T", err)
+			}
+			if exprErr.Position < 1 || exprErr.Position > len([]rune(input))+1 {
+				t.Fatalf("position %d outside of %q", exprErr.Position, input)
+			}
+			return
+		}
+
+		rendered := expr.String()
+		reparsed, err := filter.Parse(rendered, services.FilterFields)
+		if err != nil {
+			t.Fatalf("%q rendered as %q which does not parse: %v", input, rendered, err)
+		}
+		if reparsed.String() != rendered {
+			t.Fatalf("%q rendered as %q, then as %q", input, rendered, reparsed.String())
+		}
+	})
+}
@@ -0,0 +1,139 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"api-contacts-go/internal/handlers"
+	"api-contacts-go/internal/models"
+	"api-contacts-go/internal/services"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchHighlights(t *testing.T) {
+	db := setupSearchDB(t)
+	app := setupTestApp(db)
+
+	contacts := []models.CreateContactRequest{
+		{Name: "José Conceição", Email: "jose@conceicao.com.br", Company: "Conceição Imóveis"},
+		{Name: "Filipe Sousa", Email: "filipe@example.com", Phone: "(11) 98765-4321"},
+		{Name: "Mariana Lima", Email: "ana.lima@example.com"},
+	}
+	for _, contact := range contacts {
+		jsonData, _ := json.Marshal(contact)
+		req := httptest.NewRequest("POST", "/api/v1/contacts", bytes.NewBuffer(jsonData))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 201, resp.StatusCode)
+	}
+
+	search := func(query string) (int, []map[string]any) {
+		req := httptest.NewRequest("GET", "/api/v1/contacts/search?"+query, nil)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+
+		var response struct {
+			Data []map[string]any `json:"data"`
+		}
+		json.NewDecoder(resp.Body).Decode(&response)
+		return resp.StatusCode, response.Data
+	}
+	highlights := func(contact map[string]any) []models.Highlight {
+		data, _ := json.Marshal(contact["highlights"])
+		var highlights []models.Highlight
+		json.Unmarshal(data, &highlights)
+		return highlights
+	}
+
+	// Ranges count characters of the stored text, whatever its accents
+	status, data := search("q=CONCEICAO&highlight=true")
+	assert.Equal(t, 200, status)
+	assert.Len(t, data, 1)
+	assert.Equal(t, []models.Highlight{
+		{Field: "name", Text: "José Conceição", Matches: []models.MatchRange{{Start: 5, End: 14}}},
+		{Field: "email", Text: "jose@conceicao.com.br", Matches: []models.MatchRange{{Start: 5, End: 14}}},
+		{Field: "company", Text: "Conceição Imóveis", Matches: []models.MatchRange{{Start: 0, End: 9}}},
+	}, highlights(data[0]))
+
+	// Every word of the query is located, overlaps merged: the whole email
+	// covers the part of it matched as a word
+	_, data = search("q=ana.lima%40example.com%20lima&highlight=true")
+	assert.Len(t, data, 1)
+	assert.Equal(t, []models.Highlight{
+		{Field: "name", Text: "Mariana Lima", Matches: []models.MatchRange{{Start: 8, End: 12}}},
+		{Field: "email", Text: "ana.lima@example.com", Matches: []models.MatchRange{{Start: 0, End: 20}}},
+	}, highlights(data[0]))
+
+	// Only whole words are marked, as only whole words match
+	_, data = search("q=ana&highlight=true")
+	assert.Len(t, data, 1)
+	assert.Equal(t, []models.Highlight{
+		{Field: "email", Text: "ana.lima@example.com", Matches: []models.MatchRange{{Start: 0, End: 3}}},
+	}, highlights(data[0]))
+
+	// Sound-alike and similar words are located as a whole
+	_, data = search("q=felipe%20souza&mode=phonetic&highlight=true")
+	assert.Equal(t, []models.Highlight{
+		{Field: "name", Text: "Filipe Sousa", Matches: []models.MatchRange{{Start: 0, End: 6}, {Start: 7, End: 12}}},
+	}, highlights(data[0]))
+
+	_, data = search("q=filip&mode=fuzzy&highlight=true")
+	assert.Equal(t, []models.Highlight{
+		{Field: "name", Text: "Filipe Sousa", Matches: []models.MatchRange{{Start: 0, End: 6}}},
+		{Field: "email", Text: "filipe@example.com", Matches: []models.MatchRange{{Start: 0, End: 6}}},
+	}, highlights(data[0]))
+
+	// Phone matches leave the other fields unmarked
+	_, data = search("q=98765&highlight=true")
+	assert.Len(t, data, 1)
+	assert.Equal(t, []any{}, data[0]["highlights"])
+
+	// Sparse fieldsets keep the highlights
+	_, data = search("q=conceicao&highlight=true&fields=id")
+	assert.Len(t, data[0], 2)
+	assert.Len(t, highlights(data[0]), 3)
+
+	_, data = search("q=conceicao")
+	assert.NotContains(t, data[0], "highlights")
+
+	status, _ = search("q=conceicao&highlight=maybe")
+	assert.Equal(t, 400, status)
+}
+
+func TestIndexedSearchHighlightsMergeOverlaps(t *testing.T) {
+	db := setupTestDB()
+	index, err := services.OpenDiskIndex(filepath.Join(t.TempDir(), "search.idx"))
+	assert.NoError(t, err)
+	t.Cleanup(func() { index.Close() })
+	app := fiber.New()
+	handlers.SetupRoutes(app.Group("/api/v1"), db, index)
+
+	jsonData, _ := json.Marshal(models.CreateContactRequest{Name: "Mariana Lima", Email: "ana.lima@example.com"})
+	req := httptest.NewRequest("POST", "/api/v1/contacts", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 201, resp.StatusCode)
+
+	req = httptest.NewRequest("GET", "/api/v1/contacts/search?q=ana.lima%40example.com%20lima%20ana&highlight=true", nil)
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var response struct {
+		Data []models.ContactResponse `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&response)
+	assert.Len(t, response.Data, 1)
+	assert.Equal(t, &[]models.Highlight{
+		{Field: "name", Text: "Mariana Lima", Matches: []models.MatchRange{{Start: 8, End: 12}}},
+		{Field: "email", Text: "ana.lima@example.com", Matches: []models.MatchRange{{Start: 0, End: 20}}},
+	}, response.Data[0].Highlights)
+}
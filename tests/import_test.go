@@ -0,0 +1,147 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"api-contacts-go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/encoding/charmap"
+)
+
+func TestImportContacts(t *testing.T) {
+	db := setupTestDB()
+	app := setupTestApp(db)
+
+	createCustomField(t, app, models.CreateCustomFieldRequest{Name: "seats", Type: "number"})
+
+	existing, _ := json.Marshal(models.CreateContactRequest{Name: "Ana Lima", Email: "ana@example.com", Company: "Acme"})
+	req := httptest.NewRequest("POST", "/api/v1/contacts", bytes.NewBuffer(existing))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 201, resp.StatusCode)
+
+	// The same invalid contact sent to POST /contacts, for its messages
+	invalid, _ := json.Marshal(models.CreateContactRequest{Name: "X", Email: "not-an-email"})
+	req = httptest.NewRequest("POST", "/api/v1/contacts", bytes.NewBuffer(invalid))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+	var rejected map[string]string
+	json.NewDecoder(resp.Body).Decode(&rejected)
+
+	importCSV := func(query url.Values, body []byte) (int, models.ImportReport) {
+		req := httptest.NewRequest("POST", "/api/v1/contacts/import?"+query.Encode(), bytes.NewReader(body))
+		req.Header.Set("Content-Type", "text/csv")
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+
+		var report models.ImportReport
+		json.NewDecoder(resp.Body).Decode(&report)
+		return resp.StatusCode, report
+	}
+	count := func() int64 {
+		var total int64
+		db.Model(&models.Contact{}).Count(&total)
+		return total
+	}
+
+	// A Latin-1 spreadsheet with its own headers and semicolons
+	latin1, _ := charmap.ISO8859_1.NewEncoder().String("Nome;E-mail;Empresa;Licenças;Obs\n" +
+		"José Conceição;jose@example.com;Padaria São João;3;cliente antigo\n" +
+		"Ana Lima;ana@example.com;Acme Brasil;;\n" +
+		"X;not-an-email;;;\n" +
+		";;;;\n" +
+		"Maria Souza;maria@example.com;;muitas;\n" +
+		"José C.;JOSE@example.com;;;\n")
+	query := url.Values{
+		"delimiter": {";"},
+		"encoding":  {"latin1"},
+		"mapping":   {`{"Nome": "name", "E-mail": "email", "Empresa": "company", "Licenças": "cf.seats", "Obs": ""}`},
+		"dry_run":   {"true"},
+	}
+
+	// A dry run reports without saving
+	status, report := importCSV(query, []byte(latin1))
+	assert.Equal(t, 200, status)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, []string{"Obs"}, report.IgnoredColumns)
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, 3, report.Skipped)
+	assert.Equal(t, 2, report.Failed)
+	assert.Equal(t, int64(1), count())
+
+	statuses := []string{}
+	for _, row := range report.Rows {
+		statuses = append(statuses, row.Status)
+	}
+	assert.Equal(t, []string{"created", "skipped", "failed", "skipped", "failed", "skipped"}, statuses)
+	assert.Equal(t, 3, report.Rows[1].Line)
+	assert.Equal(t, uint(1), *report.Rows[1].ContactID)
+	assert.Equal(t, rejected["error"], report.Rows[2].Error)
+	assert.Equal(t, rejected["details"], report.Rows[2].Details)
+	assert.Equal(t, "empty row", report.Rows[3].Reason)
+	assert.Equal(t, "custom field 'seats' must be a number", report.Rows[4].Details)
+
+	// Then for real, updating the contacts already there
+	query.Set("dry_run", "false")
+	query.Set("existing", "update")
+	status, report = importCSV(query, []byte(latin1))
+	assert.Equal(t, 200, status)
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, 2, report.Updated)
+	assert.Equal(t, 1, report.Skipped)
+	assert.Equal(t, 2, report.Failed)
+	assert.Equal(t, int64(2), count())
+
+	var jose models.Contact
+	db.Where("email = ?", "jose@example.com").First(&jose)
+	assert.Equal(t, "José C.", jose.Name)
+	assert.Equal(t, "Padaria São João", jose.Company)
+	assert.Equal(t, float64(3), jose.CustomFields["seats"])
+
+	var ana models.Contact
+	db.First(&ana, 1)
+	assert.Equal(t, "Acme Brasil", ana.Company)
+
+	// Files can be uploaded as a form, with columns named after the fields
+	var form bytes.Buffer
+	writer := multipart.NewWriter(&form)
+	part, _ := writer.CreateFormFile("file", "contacts.csv")
+	part.Write([]byte("\xef\xbb\xbfname;email;phone\nPedro Alves;pedro@example.com;(11) 98765-4321\n"))
+	writer.WriteField("delimiter", ";")
+	writer.Close()
+
+	req = httptest.NewRequest("POST", "/api/v1/contacts/import", &form)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	json.NewDecoder(resp.Body).Decode(&report)
+	assert.Equal(t, 1, report.Created)
+	assert.Empty(t, report.IgnoredColumns)
+	assert.Equal(t, int64(3), count())
+
+	// Files or options that cannot be read fail as a whole
+	for _, tt := range []struct {
+		query url.Values
+		body  string
+	}{
+		{url.Values{}, ""},
+		{url.Values{}, "nome,telefone\nAna,123\n"},
+		{url.Values{"encoding": {"ebcdic"}}, "name,email\n"},
+		{url.Values{"delimiter": {"::"}}, "name,email\n"},
+		{url.Values{"mapping": {`{"name": "nickname"}`}}, "name,email\n"},
+		{url.Values{"mapping": {`{"Nome": "name"}`}}, "name,email\n"},
+		{url.Values{"existing": {"merge"}}, "name,email\n"},
+	} {
+		status, _ := importCSV(tt.query, []byte(tt.body))
+		assert.Equal(t, 400, status, tt.query.Encode())
+	}
+}
@@ -0,0 +1,88 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"api-contacts-go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContactListMembership(t *testing.T) {
+	db := setupTestDB()
+	app := setupTestApp(db)
+
+	for i := 1; i <= 3; i++ {
+		db.Create(&models.Contact{Name: fmt.Sprintf("Guest %d", i), Email: fmt.Sprintf("guest%d@example.com", i)})
+	}
+
+	jsonData, _ := json.Marshal(models.CreateContactListRequest{Name: "Event invitees"})
+	req := httptest.NewRequest("POST", "/api/v1/lists", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 201, resp.StatusCode)
+
+	// Names are unique regardless of case
+	jsonData, _ = json.Marshal(models.CreateContactListRequest{Name: "EVENT Invitees"})
+	req = httptest.NewRequest("POST", "/api/v1/lists", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 409, resp.StatusCode)
+
+	changeMembers := func(method string, ids []uint) (int, models.ContactList) {
+		jsonData, _ := json.Marshal(models.ListMembersRequest{ContactIDs: ids})
+		req := httptest.NewRequest(method, "/api/v1/lists/1/members", bytes.NewBuffer(jsonData))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+
+		var list models.ContactList
+		json.NewDecoder(resp.Body).Decode(&list)
+		return resp.StatusCode, list
+	}
+
+	status, list := changeMembers("POST", []uint{1, 2, 3})
+	assert.Equal(t, 200, status)
+	assert.Equal(t, int64(3), list.MemberCount)
+
+	// Adding twice is harmless, unknown contacts are rejected
+	status, list = changeMembers("POST", []uint{1})
+	assert.Equal(t, 200, status)
+	assert.Equal(t, int64(3), list.MemberCount)
+	status, _ = changeMembers("POST", []uint{1, 99})
+	assert.Equal(t, 400, status)
+
+	status, list = changeMembers("DELETE", []uint{2})
+	assert.Equal(t, 200, status)
+	assert.Equal(t, int64(2), list.MemberCount)
+
+	req = httptest.NewRequest("GET", "/api/v1/lists/1/members?limit=1", nil)
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var members models.PaginatedResponse
+	err = json.NewDecoder(resp.Body).Decode(&members)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), members.Total)
+	assert.Equal(t, 2, members.TotalPages)
+
+	// Membership is shown on the contact
+	req = httptest.NewRequest("GET", "/api/v1/contacts/1", nil)
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+
+	var contact models.ContactResponse
+	err = json.NewDecoder(resp.Body).Decode(&contact)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.ContactListSummary{{ID: 1, Name: "Event invitees"}}, contact.Lists)
+}
@@ -0,0 +1,88 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"api-contacts-go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContactNotes(t *testing.T) {
+	db := setupTestDB()
+	app := setupTestApp(db)
+
+	contact := &models.Contact{Name: "Test User", Email: "test@example.com"}
+	db.Create(contact)
+
+	notes := []models.CreateNoteRequest{
+		{Body: "Met at the **conference**", Author: "ana"},
+		{Body: "Prefers WhatsApp", Author: "ana", Pinned: true},
+		{Body: "Sent proposal", Author: "carlos"},
+	}
+	for _, note := range notes {
+		jsonData, _ := json.Marshal(note)
+		req := httptest.NewRequest("POST", "/api/v1/contacts/1/notes", bytes.NewBuffer(jsonData))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 201, resp.StatusCode)
+	}
+
+	req := httptest.NewRequest("GET", "/api/v1/contacts/1/notes?limit=2", nil)
+	resp, err := app.Test(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var response models.PaginatedNoteResponse
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), response.Total)
+	assert.Equal(t, 2, response.TotalPages)
+	assert.Len(t, response.Data, 2)
+	assert.Equal(t, "Prefers WhatsApp", response.Data[0].Body)
+
+	// Editing the body records the edit time
+	body := "Sent proposal v2"
+	jsonData, _ := json.Marshal(models.UpdateNoteRequest{Body: &body})
+	req = httptest.NewRequest("PUT", "/api/v1/contacts/1/notes/3", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var note models.Note
+	err = json.NewDecoder(resp.Body).Decode(&note)
+	assert.NoError(t, err)
+	assert.NotNil(t, note.EditedAt)
+
+	// The contact exposes the count and the pinned note
+	req = httptest.NewRequest("GET", "/api/v1/contacts/1", nil)
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+
+	var contactResponse models.ContactResponse
+	err = json.NewDecoder(resp.Body).Decode(&contactResponse)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), contactResponse.NoteCount)
+	if assert.NotNil(t, contactResponse.PinnedNote) {
+		assert.Equal(t, "Prefers WhatsApp", contactResponse.PinnedNote.Body)
+	}
+
+	req = httptest.NewRequest("DELETE", "/api/v1/contacts/1/notes/2", nil)
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 204, resp.StatusCode)
+
+	req = httptest.NewRequest("DELETE", "/api/v1/contacts/1/notes/2", nil)
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 404, resp.StatusCode)
+}
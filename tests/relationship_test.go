@@ -0,0 +1,65 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"api-contacts-go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContactRelationships(t *testing.T) {
+	db := setupTestDB()
+	app := setupTestApp(db)
+
+	for i, name := range []string{"Ana", "Bruno", "Carla"} {
+		db.Create(&models.Contact{Name: name, Email: fmt.Sprintf("user%d@example.com", i)})
+	}
+
+	relate := func(contactID uint, req models.CreateRelationshipRequest) int {
+		jsonData, _ := json.Marshal(req)
+		httpReq := httptest.NewRequest("POST", fmt.Sprintf("/api/v1/contacts/%d/relationships", contactID), bytes.NewBuffer(jsonData))
+		httpReq.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(httpReq)
+		assert.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	// Ana manages Bruno, who manages Carla
+	assert.Equal(t, 201, relate(2, models.CreateRelationshipRequest{RelatedContactID: 1, Type: models.RelationshipManager, Bidirectional: true}))
+	assert.Equal(t, 201, relate(2, models.CreateRelationshipRequest{RelatedContactID: 3, Type: models.RelationshipReport}))
+
+	// Carla cannot become Ana's manager, nor relate to herself
+	assert.Equal(t, 400, relate(1, models.CreateRelationshipRequest{RelatedContactID: 3, Type: models.RelationshipManager}))
+	assert.Equal(t, 400, relate(3, models.CreateRelationshipRequest{RelatedContactID: 3, Type: models.RelationshipSpouse}))
+	assert.Equal(t, 400, relate(1, models.CreateRelationshipRequest{RelatedContactID: 3, Type: models.RelationshipCustom}))
+	assert.Equal(t, 409, relate(2, models.CreateRelationshipRequest{RelatedContactID: 1, Type: models.RelationshipManager}))
+
+	req := httptest.NewRequest("GET", "/api/v1/contacts/1/relationships", nil)
+	resp, err := app.Test(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var relationships []models.RelationshipResponse
+	err = json.NewDecoder(resp.Body).Decode(&relationships)
+	assert.NoError(t, err)
+	assert.Len(t, relationships, 1)
+	assert.Equal(t, models.RelationshipReport, relationships[0].Type)
+	assert.Equal(t, "Bruno", relationships[0].RelatedContact.Name)
+
+	// Deleting Bruno removes every link pointing at him
+	req = httptest.NewRequest("DELETE", "/api/v1/contacts/2", nil)
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 204, resp.StatusCode)
+
+	var count int64
+	db.Model(&models.Relationship{}).Count(&count)
+	assert.Equal(t, int64(0), count)
+}
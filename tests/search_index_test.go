@@ -0,0 +1,296 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"api-contacts-go/internal/handlers"
+	"api-contacts-go/internal/models"
+	"api-contacts-go/internal/services"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiskSearchIndex(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "search.idx")
+	index, err := services.OpenDiskIndex(path)
+	assert.NoError(t, err)
+	assert.True(t, index.Empty())
+	t.Cleanup(func() { index.Close() })
+
+	db := setupTestDB()
+	app := fiber.New()
+	handlers.SetupRoutes(app.Group("/api/v1"), db, index)
+
+	send := func(method, url string, body any) int {
+		jsonData, _ := json.Marshal(body)
+		req := httptest.NewRequest(method, url, bytes.NewBuffer(jsonData))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		return resp.StatusCode
+	}
+	searchPage := func(query string) models.PaginatedResponse {
+		req := httptest.NewRequest("GET", "/api/v1/contacts/search?"+query, nil)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var response models.PaginatedResponse
+		json.NewDecoder(resp.Body).Decode(&response)
+		return response
+	}
+	search := func(query string) []string {
+		names := []string{}
+		for _, contact := range searchPage(query).Data {
+			names = append(names, contact.Name)
+		}
+		return names
+	}
+
+	contacts := []models.CreateContactRequest{
+		{Name: "João Conceição", Email: "joao@techcorp.com", Company: "Tech Corp", Phone: "(11) 98765-4321"},
+		{Name: "Filipe Sousa", Email: "filipe@example.com", Emails: []models.ContactEmailInput{
+			{Email: "filipe@acme.com", Label: models.LabelWork},
+		}},
+		{Name: "Ana Tech", Email: "ana@example.com"},
+	}
+	for _, contact := range contacts {
+		assert.Equal(t, 201, send("POST", "/api/v1/contacts", contact))
+	}
+	assert.False(t, index.Empty())
+
+	// Whole words match, ignoring accents, and are ranked by field
+	assert.Equal(t, []string{"Ana Tech", "João Conceição"}, search("q=tech"))
+	assert.Equal(t, []string{"João Conceição"}, search("q=CONCEI%C3%87%C3%83O"))
+	assert.Empty(t, search("q=concei"))
+	assert.Equal(t, []string{"Filipe Sousa"}, search("q=acme"))
+	assert.Equal(t, []string{"Ana Tech", "Filipe Sousa"}, search("q=ana%20or%20acme"))
+	assert.Equal(t, []string{"Filipe Sousa"}, search("q=felipe%20souza&mode=phonetic"))
+	assert.Equal(t, []string{"João Conceição"}, search("q=98765"))
+	assert.Equal(t, []string{"Filipe Sousa"}, search("q=filip&mode=fuzzy&threshold=0.5"))
+
+	// The index sorts and pages the matches
+	assert.Equal(t, []string{"João Conceição", "Ana Tech"}, search("q=tech&sort=-name"))
+	page := searchPage("q=tech&limit=1&page=2")
+	assert.Equal(t, int64(2), page.Total)
+	assert.Len(t, page.Data, 1)
+	assert.Equal(t, "João Conceição", page.Data[0].Name)
+
+	// Filters and facets still apply to the matches
+	assert.Equal(t, []string{"João Conceição"}, search("q=tech&company=tech%20corp"))
+	assert.Equal(t, []models.FacetBucket{{Value: "Tech Corp", Count: 1}}, searchPage("q=tech&facets=company").Facets["company"])
+
+	// Updates and deletions reach the index
+	assert.Equal(t, 200, send("PUT", "/api/v1/contacts/2", models.UpdateContactRequest{Name: stringPtr("Felipe Souza")}))
+	assert.Empty(t, search("q=filipe%20sousa"))
+	assert.Equal(t, []string{"Felipe Souza"}, search("q=souza"))
+
+	assert.Equal(t, 204, send("DELETE", "/api/v1/contacts/3", nil))
+	assert.Equal(t, []string{"João Conceição"}, search("q=tech"))
+
+	// So do company renames
+	assert.Equal(t, 200, send("PUT", "/api/v1/companies/1", models.UpdateCompanyRequest{Name: stringPtr("Globex")}))
+	assert.Equal(t, []string{"João Conceição"}, search("q=globex"))
+
+	// Nothing is left marked once the index caught up
+	var pending int64
+	db.Model(&models.SearchIndexUpdate{}).Count(&pending)
+	assert.Equal(t, int64(0), pending)
+
+	// A second process cannot open the index while it is in use
+	_, err = services.OpenDiskIndex(path)
+	assert.ErrorIs(t, err, services.ErrSearchIndexLocked)
+
+	// The index survives restarts, from its log and once compacted
+	restart := func() {
+		assert.NoError(t, index.Close())
+		index, err = services.OpenDiskIndex(path)
+		assert.NoError(t, err)
+		app = fiber.New()
+		handlers.SetupRoutes(app.Group("/api/v1"), db, index)
+	}
+	globex := []services.SearchHit{{ID: 1, Score: 0.4}}
+	restart()
+	hits, err := index.Match(services.SearchOptions{Query: "globex", Mode: services.SearchModeText}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, globex, hits)
+
+	assert.NoError(t, index.Compact())
+	_, err = os.Stat(path + ".log.old")
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+	restart()
+	hits, err = index.Match(services.SearchOptions{Query: "globex", Mode: services.SearchModeText}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, globex, hits)
+
+	// A change cut short by a crash is dropped from the log
+	assert.Equal(t, 200, send("PUT", "/api/v1/contacts/1", models.UpdateContactRequest{Company: stringPtr("Initech")}))
+	log, err := os.OpenFile(path+".log", os.O_WRONLY|os.O_APPEND, 0o644)
+	assert.NoError(t, err)
+	log.WriteString(`{"id":2,"document":{"Na`)
+	log.Close()
+	restart()
+	hits, err = index.Match(services.SearchOptions{Query: "initech", Mode: services.SearchModeText}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []services.SearchHit{{ID: 1, Score: 0.4}}, hits)
+	hits, err = index.Match(services.SearchOptions{Query: "souza", Mode: services.SearchModeText}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []services.SearchHit{{ID: 2, Score: 1.0}}, hits)
+
+	// Reindexing rebuilds a lost index from the database
+	fresh, err := services.OpenDiskIndex(filepath.Join(t.TempDir(), "search.idx"))
+	assert.NoError(t, err)
+	t.Cleanup(func() { fresh.Close() })
+	indexed, err := services.NewContactService(db, fresh).Reindex()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, indexed)
+	app = fiber.New()
+	handlers.SetupRoutes(app.Group("/api/v1"), db, fresh)
+	assert.Equal(t, []string{"Felipe Souza"}, search("q=acme"))
+	assert.Equal(t, []string{"João Conceição"}, search("q=initech"))
+
+	_, err = services.NewSearchIndex("elastic", path)
+	assert.Error(t, err)
+}
+
+func TestDiskSearchIndexSortCollation(t *testing.T) {
+	index, err := services.OpenDiskIndex(filepath.Join(t.TempDir(), "search.idx"))
+	assert.NoError(t, err)
+	t.Cleanup(func() { index.Close() })
+
+	// Byte order would put digits before punctuation such as @ and _
+	err = index.Index(
+		models.Contact{ID: 1, Name: "Ana1 Lima", Email: "ana1@acme.com", Company: "Acme"},
+		models.Contact{ID: 2, Name: "Ana_Lima", Email: "ana@acme.com", Company: "Acme"},
+		models.Contact{ID: 3, Name: "Ana Lima", Email: "ana.lima@acme.com", Company: "Acme"},
+	)
+	assert.NoError(t, err)
+
+	ids := func(field string) []uint {
+		hits, err := index.Match(services.SearchOptions{Query: "acme"}, []services.SortField{{Field: field}})
+		assert.NoError(t, err)
+		ids := []uint{}
+		for _, hit := range hits {
+			ids = append(ids, hit.ID)
+		}
+		return ids
+	}
+	assert.Equal(t, []uint{3, 2, 1}, ids("name"))
+	assert.Equal(t, []uint{3, 2, 1}, ids("email"))
+}
+
+// failingIndex is a search index whose updates always fail
+type failingIndex struct {
+	*services.DiskIndex
+}
+
+func (failingIndex) Index(contacts ...models.Contact) error {
+	return errors.New("index unavailable")
+}
+
+func TestSearchIndexCatchesUp(t *testing.T) {
+	index, err := services.OpenDiskIndex(filepath.Join(t.TempDir(), "search.idx"))
+	assert.NoError(t, err)
+	t.Cleanup(func() { index.Close() })
+
+	db := setupTestDB()
+	app := fiber.New()
+	handlers.SetupRoutes(app.Group("/api/v1"), db, failingIndex{index})
+
+	// The contact is saved even though the index could not be updated
+	jsonData, _ := json.Marshal(models.CreateContactRequest{Name: "Paula Reis", Email: "paula@example.com"})
+	req := httptest.NewRequest("POST", "/api/v1/contacts", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 201, resp.StatusCode)
+
+	hits, err := index.Match(services.SearchOptions{Query: "paula"}, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, hits)
+
+	// It stays marked until the index catches up, as on the next start
+	var pending []models.SearchIndexUpdate
+	db.Find(&pending)
+	assert.Equal(t, []models.SearchIndexUpdate{{ContactID: 1, Revision: 1}}, pending)
+
+	synced, err := services.NewContactService(db, index).SyncSearchIndex()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, synced)
+	hits, err = index.Match(services.SearchOptions{Query: "paula"}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []services.SearchHit{{ID: 1, Score: 1.2}}, hits)
+
+	db.Find(&pending)
+	assert.Empty(t, pending)
+}
+
+func TestSearchIndexRebuildsWhileServing(t *testing.T) {
+	index, err := services.OpenDiskIndex(filepath.Join(t.TempDir(), "search.idx"))
+	assert.NoError(t, err)
+	t.Cleanup(func() { index.Close() })
+
+	db := setupTestDB()
+	app := fiber.New()
+	handlers.SetupRoutes(app.Group("/api/v1"), db, index)
+
+	send := func(method, url string, body any) *http.Response {
+		jsonData, _ := json.Marshal(body)
+		req := httptest.NewRequest(method, url, bytes.NewBuffer(jsonData))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		return resp
+	}
+	match := func(query string) []uint {
+		hits, err := index.Match(services.SearchOptions{Query: query}, nil)
+		assert.NoError(t, err)
+		ids := []uint{}
+		for _, hit := range hits {
+			ids = append(ids, hit.ID)
+		}
+		return ids
+	}
+
+	for _, name := range []string{"Filipe Sousa", "Paula Reis"} {
+		contact := models.CreateContactRequest{Name: name, Email: strings.ToLower(strings.Fields(name)[0]) + "@example.com"}
+		assert.Equal(t, 201, send("POST", "/api/v1/contacts", contact).StatusCode)
+	}
+
+	// Searches and changes go on while the rebuild reads the database, and
+	// the changes outlive the older rows it read
+	err = index.Rebuild(func(add func(contacts []models.Contact)) error {
+		var contacts []models.Contact
+		db.Find(&contacts)
+
+		assert.Equal(t, 200, send("PUT", "/api/v1/contacts/1", models.UpdateContactRequest{Name: stringPtr("Felipe Souza")}).StatusCode)
+		assert.Equal(t, 204, send("DELETE", "/api/v1/contacts/2", nil).StatusCode)
+		assert.Equal(t, []uint{1}, match("souza"))
+
+		add(contacts)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{1}, match("souza"))
+	assert.Empty(t, match("sousa"))
+	assert.Empty(t, match("paula"))
+
+	// The API rebuilds its own index
+	resp := send("POST", "/api/v1/contacts/reindex", nil)
+	assert.Equal(t, 200, resp.StatusCode)
+	var rebuilt models.ReindexResponse
+	json.NewDecoder(resp.Body).Decode(&rebuilt)
+	assert.Equal(t, 1, rebuilt.Indexed)
+	assert.Equal(t, []uint{1}, match("souza"))
+}
@@ -0,0 +1,314 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	"api-contacts-go/internal/models"
+	"api-contacts-go/internal/services"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchRanking(t *testing.T) {
+	db := setupSearchDB(t)
+	app := setupTestApp(db)
+
+	contacts := []models.Contact{
+		{Name: "Ana Costa", Email: "ana@example.com", Company: "Tech Corp"},
+		{Name: "Bruno Tech", Email: "bruno@example.com", Company: "Acme"},
+		{Name: "Carla Lima", Email: "carla@example.com", Company: "Lima Tech"},
+	}
+	for _, contact := range contacts {
+		db.Create(&contact)
+	}
+
+	search := func(query string) models.PaginatedResponse {
+		req := httptest.NewRequest("GET", "/api/v1/contacts/search?q="+query, nil)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var response models.PaginatedResponse
+		err = json.NewDecoder(resp.Body).Decode(&response)
+		assert.NoError(t, err)
+		return response
+	}
+
+	// A name match outranks company matches
+	response := search("tech")
+	assert.Equal(t, int64(3), response.Total)
+	assert.Equal(t, "Bruno Tech", response.Data[0].Name)
+	if assert.NotNil(t, response.Data[0].Score) && assert.NotNil(t, response.Data[1].Score) {
+		assert.Greater(t, *response.Data[0].Score, *response.Data[1].Score)
+	}
+
+	// Every word of the query must match
+	response = search("ana%20tech")
+	assert.Equal(t, int64(1), response.Total)
+	assert.Equal(t, "Ana Costa", response.Data[0].Name)
+}
+
+func TestFuzzySearch(t *testing.T) {
+	db := setupSearchDB(t)
+	app := setupTestApp(db)
+
+	contacts := []models.Contact{
+		{Name: "Pedro Oliveira", Email: "pedro@example.com"},
+		{Name: "Maria Santos", Email: "maria@example.com"},
+	}
+	for _, contact := range contacts {
+		db.Create(&contact)
+	}
+
+	search := func(query string) (int, models.PaginatedResponse) {
+		req := httptest.NewRequest("GET", "/api/v1/contacts/search?"+query, nil)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+
+		var response models.PaginatedResponse
+		json.NewDecoder(resp.Body).Decode(&response)
+		return resp.StatusCode, response
+	}
+
+	// The misspelling only matches in fuzzy mode
+	status, response := search("q=Olivera")
+	assert.Equal(t, 200, status)
+	assert.Equal(t, int64(0), response.Total)
+
+	status, response = search("q=Olivera&mode=fuzzy&threshold=0.3")
+	assert.Equal(t, 200, status)
+	if assert.Equal(t, int64(1), response.Total) {
+		assert.Equal(t, "Pedro Oliveira", response.Data[0].Name)
+		assert.InDelta(t, 0.625, *response.Data[0].Score, 0.001)
+	}
+
+	status, response = search("q=Olivera&mode=fuzzy&threshold=0.9")
+	assert.Equal(t, 200, status)
+	assert.Equal(t, int64(0), response.Total)
+
+	// The threshold only holds for the search that set it
+	status, response = search("q=Olivera&mode=fuzzy&threshold=0.6")
+	assert.Equal(t, 200, status)
+	assert.Equal(t, int64(1), response.Total)
+
+	status, _ = search("q=Olivera&mode=soundex")
+	assert.Equal(t, 400, status)
+}
+
+func TestFuzzyScoresAgreeWithIndex(t *testing.T) {
+	db := setupSearchDB(t)
+	app := setupTestApp(db)
+
+	contacts := []models.Contact{
+		{Name: "Pedro Oliveira", Email: "pedro.oliveira@example.com", Company: "Oliveira & Filhos"},
+		{Name: "Olívia Souza", Email: "olivia@example.com"},
+		{Name: "Eva Santos", Email: "eva@silva.com.br", Company: "Silva Santos"},
+		{Name: "Ana Silvestre", Email: "ana@example.com"},
+	}
+	for _, contact := range contacts {
+		db.Create(&contact)
+	}
+
+	index, err := services.OpenDiskIndex(filepath.Join(t.TempDir(), "search.idx"))
+	assert.NoError(t, err)
+	t.Cleanup(func() { index.Close() })
+	_, err = services.NewContactService(db, index).Reindex()
+	assert.NoError(t, err)
+
+	// pg_trgm and the disk index find the same matches with the same scores
+	for _, query := range []string{"olivera", "silva", "santos oliveira", "souza"} {
+		req := httptest.NewRequest("GET", "/api/v1/contacts/search?mode=fuzzy&threshold=0.3&sort=id&q="+url.QueryEscape(query), nil)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var response models.PaginatedResponse
+		json.NewDecoder(resp.Body).Decode(&response)
+		hits, err := index.Match(services.SearchOptions{Query: query, Mode: services.SearchModeFuzzy, Threshold: 0.3},
+			[]services.SortField{{Field: "id"}})
+		assert.NoError(t, err)
+
+		if assert.Len(t, hits, len(response.Data), query) {
+			for i, contact := range response.Data {
+				assert.Equal(t, contact.ID, hits[i].ID, query)
+				assert.InDelta(t, *contact.Score, hits[i].Score, 0.0001, query)
+			}
+		}
+	}
+}
+
+func TestPhoneticSearch(t *testing.T) {
+	db := setupTestDB()
+	app := setupTestApp(db)
+
+	contacts := []models.Contact{
+		{Name: "Filipe Sousa", Email: "filipe@example.com"},
+		{Name: "Felipe Souza", Email: "felipe@example.com"},
+		{Name: "Rafael Costa", Email: "rafael@example.com"},
+	}
+	for _, contact := range contacts {
+		db.Create(&contact)
+	}
+
+	search := func(query string) models.PaginatedResponse {
+		req := httptest.NewRequest("GET", "/api/v1/contacts/search?mode=phonetic&q="+query, nil)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var response models.PaginatedResponse
+		err = json.NewDecoder(resp.Body).Decode(&response)
+		assert.NoError(t, err)
+		return response
+	}
+
+	// Both spellings match, the exact one first
+	response := search("felipe%20souza")
+	if assert.Equal(t, int64(2), response.Total) {
+		assert.Equal(t, "Felipe Souza", response.Data[0].Name)
+		assert.Equal(t, "Filipe Sousa", response.Data[1].Name)
+	}
+
+	response = search("Raphael")
+	if assert.Equal(t, int64(1), response.Total) {
+		assert.Equal(t, "Rafael Costa", response.Data[0].Name)
+	}
+
+	// LIKE wildcards in the query do not count as the exact spelling
+	response = search("rafael%25%20costa")
+	if assert.Equal(t, int64(1), response.Total) && assert.NotNil(t, response.Data[0].Score) {
+		assert.Equal(t, 0.5, *response.Data[0].Score)
+	}
+
+	// The key follows renames
+	var contact models.Contact
+	db.First(&contact, "name = ?", "Rafael Costa")
+	contact.Name = "Thiago Costa"
+	db.Save(&contact)
+
+	assert.Equal(t, int64(0), search("Raphael").Total)
+	assert.Equal(t, int64(1), search("Tiago").Total)
+}
+
+func TestPhoneticKey(t *testing.T) {
+	assert.Equal(t, models.PhoneticKey("Filipe Sousa"), models.PhoneticKey("Felipe Souza"))
+	assert.Equal(t, models.PhoneticKey("Gonçalves"), models.PhoneticKey("Gonsalves"))
+	assert.Equal(t, models.PhoneticKey("Conceição"), models.PhoneticKey("Conseisão"))
+	assert.Equal(t, models.PhoneticKey("GONÇALVES"), models.PhoneticKey("Gonc\u0327alves"))
+	assert.Equal(t, models.PhoneticKey("Guilherme"), models.PhoneticKey("Gilherme"))
+	assert.Equal(t, models.PhoneticKey("Jéssica"), models.PhoneticKey("Gessica"))
+	assert.NotEqual(t, models.PhoneticKey("Paulo"), models.PhoneticKey("Pedro"))
+}
+
+func TestSearchByPhoneDigits(t *testing.T) {
+	db := setupSearchDB(t)
+	app := setupTestApp(db)
+
+	contacts := []models.CreateContactRequest{
+		{Name: "João Silva", Email: "joao@example.com", Phone: "+55 11 99999-1111"},
+		{Name: "Maria Santos", Email: "maria@example.com", Phones: []models.ContactPhoneInput{
+			{Number: "(21) 3333-4444", Label: models.LabelWork},
+			{Number: "21 98888-2222", Label: models.LabelMobile},
+		}},
+	}
+	for _, contact := range contacts {
+		jsonData, _ := json.Marshal(contact)
+		req := httptest.NewRequest("POST", "/api/v1/contacts", bytes.NewBuffer(jsonData))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 201, resp.StatusCode)
+	}
+
+	search := func(query string) models.PaginatedResponse {
+		req := httptest.NewRequest("GET", "/api/v1/contacts/search?q="+url.QueryEscape(query), nil)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var response models.PaginatedResponse
+		err = json.NewDecoder(resp.Body).Decode(&response)
+		assert.NoError(t, err)
+		return response
+	}
+
+	// Any formatting of the number finds the same contact
+	for _, query := range []string{"11999991111", "(11) 99999-1111", "+5511999991111", "9999-1111"} {
+		response := search(query)
+		if assert.Equal(t, int64(1), response.Total, query) {
+			assert.Equal(t, "João Silva", response.Data[0].Name)
+		}
+	}
+
+	// Secondary numbers are searched too
+	response := search("2198888 2222")
+	if assert.Equal(t, int64(1), response.Total) {
+		assert.Equal(t, "Maria Santos", response.Data[0].Name)
+	}
+
+	// Numbers in names are still matched as words
+	db.Create(&models.Contact{Name: "Sala 2222", Email: "sala@example.com"})
+	response = search("2222")
+	if assert.Equal(t, int64(2), response.Total) {
+		names := []string{response.Data[0].Name, response.Data[1].Name}
+		assert.ElementsMatch(t, []string{"Maria Santos", "Sala 2222"}, names)
+	}
+
+	assert.Equal(t, "11999991111", models.PhoneDigits("+55 (11) 99999-1111"))
+	assert.Equal(t, "5533334444", models.PhoneDigits("(55) 3333-4444"))
+}
+
+func TestSearchMatchesWholeWords(t *testing.T) {
+	db := setupSearchDB(t)
+	app := setupTestApp(db)
+
+	contacts := []models.CreateContactRequest{
+		{Name: "Ana Costa", Email: "ana@example.com"},
+		{Name: "Mariana Lima", Email: "mariana@example.com", Emails: []models.ContactEmailInput{
+			{Email: "mari@pessoal.com.br", Label: models.LabelHome},
+		}},
+		{Name: "Bruno Souza", Email: "bruno@example.com"},
+	}
+	for _, contact := range contacts {
+		jsonData, _ := json.Marshal(contact)
+		req := httptest.NewRequest("POST", "/api/v1/contacts", bytes.NewBuffer(jsonData))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 201, resp.StatusCode)
+	}
+
+	search := func(query string) []string {
+		req := httptest.NewRequest("GET", "/api/v1/contacts/search?sort=name&q="+url.QueryEscape(query), nil)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var response models.PaginatedResponse
+		json.NewDecoder(resp.Body).Decode(&response)
+		names := []string{}
+		for _, contact := range response.Data {
+			names = append(names, contact.Name)
+		}
+		return names
+	}
+
+	// Words match whole, never inside another word
+	assert.Equal(t, []string{"Ana Costa"}, search("ana"))
+	assert.Equal(t, []string{}, search("cost"))
+
+	// Secondary emails are matched whole or by their parts
+	assert.Equal(t, []string{"Mariana Lima"}, search("mari@pessoal.com.br"))
+	assert.Equal(t, []string{"Mariana Lima"}, search("pessoal"))
+
+	// Alternatives and exclusions follow the web search syntax
+	assert.Equal(t, []string{"Ana Costa", "Bruno Souza"}, search("ana or bruno"))
+	assert.Equal(t, []string{"Bruno Souza", "Mariana Lima"}, search("example -ana"))
+}
@@ -0,0 +1,58 @@
+//go:build integration
+
+package tests
+
+import (
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"api-contacts-go/internal/database"
+
+	"gorm.io/gorm"
+)
+
+var (
+	migrateOnce sync.Once
+	migrateErr  error
+)
+
+// setupSearchDB connects to the PostgreSQL database of TEST_DATABASE_URL,
+// migrated and emptied, for the tests of what only PostgreSQL runs: the text
+// and fuzzy searches, on the columns, triggers and indexes of the migrations
+func setupSearchDB(t *testing.T) *gorm.DB {
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Fatal("TEST_DATABASE_URL must point to a PostgreSQL database for the integration tests")
+	}
+
+	migrateOnce.Do(func() {
+		migrateErr = database.RunMigrationsFrom(url, "../migrations")
+	})
+	if migrateErr != nil {
+		t.Fatal(migrateErr)
+	}
+
+	db, err := database.Initialize(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	var tables []string
+	err = db.Raw("SELECT tablename FROM pg_tables WHERE schemaname = current_schema() AND tablename <> 'schema_migrations'").
+		Scan(&tables).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Exec("TRUNCATE " + strings.Join(tables, ", ") + " RESTART IDENTITY CASCADE").Error; err != nil {
+		t.Fatal(err)
+	}
+
+	return db
+}
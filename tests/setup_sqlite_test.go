@@ -0,0 +1,17 @@
+//go:build !integration
+
+package tests
+
+import (
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// setupSearchDB gives the tests of the text and fuzzy searches their
+// database. Those searches only run on PostgreSQL, so without the integration
+// tag the tests are skipped.
+func setupSearchDB(t *testing.T) *gorm.DB {
+	t.Skip("text and fuzzy searches need PostgreSQL, run with -tags integration and TEST_DATABASE_URL")
+	return nil
+}
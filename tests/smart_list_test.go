@@ -0,0 +1,157 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"api-contacts-go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSmartListEvaluation(t *testing.T) {
+	db := setupTestDB()
+	app := setupTestApp(db)
+
+	db.Create(&models.Contact{Name: "Recent Corp", Email: "recent@example.com", Company: "Tech Corp"})
+	db.Create(&models.Contact{Name: "Old Corp", Email: "old@example.com", Company: "Acme Corp", CreatedAt: time.Now().AddDate(0, 0, -60)})
+	db.Create(&models.Contact{Name: "Recent Other", Email: "other@example.com", Company: "Other Ltda"})
+
+	createList := func(definition models.SmartListDefinition) (int, models.SmartList) {
+		jsonData, _ := json.Marshal(models.CreateSmartListRequest{Name: "Recent corporate", Definition: definition})
+		req := httptest.NewRequest("POST", "/api/v1/smart-lists", bytes.NewBuffer(jsonData))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+
+		var list models.SmartList
+		json.NewDecoder(resp.Body).Decode(&list)
+		return resp.StatusCode, list
+	}
+
+	// Date fields do not support text operators
+	status, _ := createList(models.SmartListDefinition{Conditions: []models.SmartListCondition{
+		{Field: "created_at", Operator: "contains", Value: "2024"},
+	}})
+	assert.Equal(t, 400, status)
+
+	status, list := createList(models.SmartListDefinition{Conditions: []models.SmartListCondition{
+		{Field: "company", Operator: "contains", Value: "corp"},
+		{Field: "created_at", Operator: "within_last_days", Value: "30"},
+	}})
+	assert.Equal(t, 201, status)
+	if assert.NotNil(t, list.MemberCount) {
+		assert.Equal(t, int64(1), *list.MemberCount)
+	}
+
+	req := httptest.NewRequest("GET", "/api/v1/smart-lists/1/contacts", nil)
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var response models.PaginatedResponse
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), response.Total)
+	assert.Equal(t, "Recent Corp", response.Data[0].Name)
+
+	// Membership is evaluated live
+	db.Create(&models.Contact{Name: "New Corp", Email: "new@example.com", Company: "New Corp"})
+
+	req = httptest.NewRequest("GET", "/api/v1/smart-lists/1", nil)
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+
+	err = json.NewDecoder(resp.Body).Decode(&list)
+	assert.NoError(t, err)
+	if assert.NotNil(t, list.MemberCount) {
+		assert.Equal(t, int64(2), *list.MemberCount)
+	}
+
+	// LIKE wildcards in a value are matched literally
+	db.Create(&models.Contact{Name: "Ana 100% Corp", Email: "ana100@example.com"})
+	wildcards := []struct {
+		condition models.SmartListCondition
+		count     int64
+	}{
+		{models.SmartListCondition{Field: "name", Operator: "contains", Value: "100%"}, 1},
+		{models.SmartListCondition{Field: "name", Operator: "starts_with", Value: "_ecent"}, 0},
+		{models.SmartListCondition{Field: "name", Operator: "contains", Value: "%corp"}, 0},
+	}
+	for i, wildcard := range wildcards {
+		jsonData, _ := json.Marshal(models.CreateSmartListRequest{
+			Name:       fmt.Sprintf("Wildcards %d", i),
+			Definition: models.SmartListDefinition{Conditions: []models.SmartListCondition{wildcard.condition}},
+		})
+		req := httptest.NewRequest("POST", "/api/v1/smart-lists", bytes.NewBuffer(jsonData))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 201, resp.StatusCode)
+
+		json.NewDecoder(resp.Body).Decode(&list)
+		if assert.NotNil(t, list.MemberCount) {
+			assert.Equal(t, wildcard.count, *list.MemberCount, wildcard.condition.Value)
+		}
+	}
+}
+
+func TestSmartListCounts(t *testing.T) {
+	db := setupTestDB()
+	app := setupTestApp(db)
+
+	company := models.Company{Name: "Acme"}
+	db.Create(&company)
+	db.Create(&models.Contact{Name: "Ana Acme", Email: "ana@acme.com", CompanyID: &company.ID})
+	db.Create(&models.SmartList{Name: "Acme", Definition: models.SmartListDefinition{CompanyID: &company.ID}})
+	db.Create(&models.SmartList{Name: "Ana", Definition: models.SmartListDefinition{Conditions: []models.SmartListCondition{
+		{Field: "name", Operator: "starts_with", Value: "ana"},
+	}}})
+
+	getLists := func(url string) (int, models.PaginatedSmartListResponse) {
+		resp, err := app.Test(httptest.NewRequest("GET", url, nil))
+		assert.NoError(t, err)
+
+		var response models.PaginatedSmartListResponse
+		json.NewDecoder(resp.Body).Decode(&response)
+		return resp.StatusCode, response
+	}
+
+	// Lists are not evaluated unless counts are asked for
+	status, response := getLists("/api/v1/smart-lists")
+	assert.Equal(t, 200, status)
+	if assert.Len(t, response.Data, 2) {
+		assert.Nil(t, response.Data[0].MemberCount)
+		assert.Nil(t, response.Data[1].MemberCount)
+	}
+
+	status, response = getLists("/api/v1/smart-lists?counts=true")
+	assert.Equal(t, 200, status)
+	if assert.Len(t, response.Data, 2) {
+		for _, list := range response.Data {
+			if assert.NotNil(t, list.MemberCount) {
+				assert.Equal(t, int64(1), *list.MemberCount)
+			}
+		}
+	}
+
+	status, _ = getLists("/api/v1/smart-lists?counts=maybe")
+	assert.Equal(t, 400, status)
+
+	// A definition broken by a removed company reports why instead of a count
+	db.Delete(&company)
+	status, response = getLists("/api/v1/smart-lists?counts=true")
+	assert.Equal(t, 200, status)
+	if assert.Len(t, response.Data, 2) {
+		assert.Equal(t, "Acme", response.Data[0].Name)
+		assert.Nil(t, response.Data[0].MemberCount)
+		assert.Contains(t, response.Data[0].DefinitionError, "does not exist")
+		assert.Empty(t, response.Data[1].DefinitionError)
+	}
+}
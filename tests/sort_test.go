@@ -0,0 +1,146 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"api-contacts-go/internal/models"
+	"api-contacts-go/internal/services"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// createSortContacts adds contacts with equal names, companies and creation
+// times, in an order none of the sorts give
+func createSortContacts(db *gorm.DB) {
+	created := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	contacts := []models.Contact{
+		{Name: "Érica Souza", Email: "erica@example.com", Company: "Acme", CreatedAt: created},
+		{Name: "bruno Lima", Email: "bruno.lima@example.com", Company: "Tech Corp", CreatedAt: created},
+		{Name: "Ana Costa", Email: "ana@example.com", Company: "Tech Corp", CreatedAt: created.AddDate(0, 0, 1)},
+		{Name: "Bruno Lima", Email: "bruno@example.com", Company: "Acme", CreatedAt: created.AddDate(0, 0, 2)},
+		{Name: "Eduardo Reis", Email: "eduardo@example.com", CreatedAt: created.AddDate(0, 0, 3)},
+	}
+	for _, contact := range contacts {
+		db.Create(&contact)
+	}
+}
+
+// listSorted lists contacts, returning their ids and the sort applied
+func listSorted(t *testing.T, app *fiber.App, path string) (int, []uint, string) {
+	req := httptest.NewRequest("GET", path, nil)
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+
+	var response models.PaginatedResponse
+	json.NewDecoder(resp.Body).Decode(&response)
+	ids := []uint{}
+	for _, contact := range response.Data {
+		ids = append(ids, contact.ID)
+	}
+	return resp.StatusCode, ids, response.Sort
+}
+
+func TestSortContacts(t *testing.T) {
+	db := setupTestDB()
+	app := setupTestApp(db)
+	createSortContacts(db)
+
+	tests := []struct {
+		path string
+		ids  []uint
+		sort string
+	}{
+		// Case and accents do not affect names, equal names fall back to id
+		{"/api/v1/contacts?sort=name", []uint{3, 2, 4, 5, 1}, "name"},
+		{"/api/v1/contacts?sort=-name", []uint{1, 5, 4, 2, 3}, "-name"},
+		{"/api/v1/contacts?sort=company,-created_at", []uint{5, 4, 1, 3, 2}, "company,-created_at"},
+		{"/api/v1/contacts?sort=created_at", []uint{1, 2, 3, 4, 5}, "created_at"},
+		{"/api/v1/contacts", []uint{5, 4, 3, 2, 1}, "-created_at"},
+	}
+	for _, tt := range tests {
+		status, ids, sort := listSorted(t, app, tt.path)
+		assert.Equal(t, 200, status, tt.path)
+		assert.Equal(t, tt.ids, ids, tt.path)
+		assert.Equal(t, tt.sort, sort, tt.path)
+	}
+
+	for _, path := range []string{
+		"/api/v1/contacts?sort=salary",
+		"/api/v1/contacts?sort=name,-name",
+		"/api/v1/contacts?sort=relevance",
+		"/api/v1/contacts?sort=name,",
+	} {
+		status, _, _ := listSorted(t, app, path)
+		assert.Equal(t, 400, status, path)
+	}
+}
+
+func TestSortSearchResults(t *testing.T) {
+	db := setupSearchDB(t)
+	app := setupTestApp(db)
+	createSortContacts(db)
+
+	// Equal scores fall back to the sort of plain listings
+	tests := []struct {
+		path string
+		ids  []uint
+		sort string
+	}{
+		{"/api/v1/contacts/search?q=lima", []uint{2, 4}, "-relevance,-created_at"},
+		{"/api/v1/contacts/search?q=lima&sort=-email", []uint{4, 2}, "-email"},
+		{"/api/v1/contacts/search?q=lima&mode=fuzzy&sort=-relevance,id", []uint{2, 4}, "-relevance,id"},
+	}
+	for _, tt := range tests {
+		status, ids, sort := listSorted(t, app, tt.path)
+		assert.Equal(t, 200, status, tt.path)
+		assert.Equal(t, tt.ids, ids, tt.path)
+		assert.Equal(t, tt.sort, sort, tt.path)
+	}
+}
+
+func TestSortCollationAgreesWithIndex(t *testing.T) {
+	db := setupSearchDB(t)
+	app := setupTestApp(db)
+
+	contacts := []models.Contact{
+		{Name: "Ana1 Lima", Email: "ana1@acme.com", Company: "Acme 2"},
+		{Name: "Ana_Lima", Email: "ana@acme.com", Company: "Acme-Sul"},
+		{Name: "Ana Lima", Email: "ana.lima@acme.com", Company: "Acme Sul"},
+		{Name: "Ânia Souza", Email: "ania_souza@acme.com", Company: "ACME"},
+		{Name: "anabela Reis", Email: "Anabela@acme.com", Company: "Acme_Norte"},
+	}
+	for _, contact := range contacts {
+		db.Create(&contact)
+	}
+
+	index, err := services.OpenDiskIndex(filepath.Join(t.TempDir(), "search.idx"))
+	assert.NoError(t, err)
+	t.Cleanup(func() { index.Close() })
+	_, err = services.NewContactService(db, index).Reindex()
+	assert.NoError(t, err)
+
+	// PostgreSQL and the index sort text in the same collation
+	for _, sort := range []string{"name", "-name", "email", "company", "-company"} {
+		fields, err := services.ParseSort(sort, true)
+		assert.NoError(t, err)
+		hits, err := index.Match(services.SearchOptions{Query: "acme"}, fields)
+		assert.NoError(t, err)
+		indexed := []uint{}
+		for _, hit := range hits {
+			indexed = append(indexed, hit.ID)
+		}
+
+		status, ids, _ := listSorted(t, app, "/api/v1/contacts/search?q=acme&sort="+sort)
+		assert.Equal(t, 200, status, sort)
+		assert.Equal(t, indexed, ids, sort)
+		status, ids, _ = listSorted(t, app, "/api/v1/contacts?sort="+sort)
+		assert.Equal(t, 200, status, sort)
+		assert.Equal(t, indexed, ids, sort)
+	}
+}
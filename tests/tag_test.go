@@ -0,0 +1,117 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"api-contacts-go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContactTags(t *testing.T) {
+	db := setupTestDB()
+	app := setupTestApp(db)
+
+	contact := &models.Contact{Name: "Test User", Email: "test@example.com"}
+	db.Create(contact)
+
+	jsonData, _ := json.Marshal(models.TagsRequest{Tags: []string{"VIP", " supplier ", "vip"}})
+	req := httptest.NewRequest("POST", "/api/v1/contacts/1/tags", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var tags []models.Tag
+	err = json.NewDecoder(resp.Body).Decode(&tags)
+	assert.NoError(t, err)
+	assert.Len(t, tags, 2)
+	assert.Equal(t, "supplier", tags[0].Name)
+	assert.Equal(t, "vip", tags[1].Name)
+
+	// Tags are rendered on the contact itself
+	req = httptest.NewRequest("GET", "/api/v1/contacts/1", nil)
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+
+	var contactResponse models.ContactResponse
+	err = json.NewDecoder(resp.Body).Decode(&contactResponse)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"supplier", "vip"}, contactResponse.Tags)
+
+	req = httptest.NewRequest("DELETE", "/api/v1/contacts/1/tags/vip", nil)
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 204, resp.StatusCode)
+
+	req = httptest.NewRequest("DELETE", "/api/v1/contacts/1/tags/unknown", nil)
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 404, resp.StatusCode)
+
+	req = httptest.NewRequest("GET", "/api/v1/tags", nil)
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var tagResponses []models.TagResponse
+	err = json.NewDecoder(resp.Body).Decode(&tagResponses)
+	assert.NoError(t, err)
+	assert.Len(t, tagResponses, 2)
+	assert.Equal(t, "supplier", tagResponses[0].Name)
+	assert.Equal(t, int64(1), tagResponses[0].ContactCount)
+	assert.Equal(t, "vip", tagResponses[1].Name)
+	assert.Equal(t, int64(0), tagResponses[1].ContactCount)
+}
+
+func TestFilterContactsByTag(t *testing.T) {
+	db := setupTestDB()
+	app := setupTestApp(db)
+
+	vip := models.Tag{Name: "vip"}
+	supplier := models.Tag{Name: "supplier"}
+	db.Create(&vip)
+	db.Create(&supplier)
+
+	contacts := []models.Contact{
+		{Name: "João Silva", Email: "joao@example.com", Tags: []models.Tag{vip, supplier}},
+		{Name: "Maria Santos", Email: "maria@example.com", Tags: []models.Tag{vip}},
+		{Name: "Pedro Oliveira", Email: "pedro@example.com"},
+	}
+	for _, contact := range contacts {
+		db.Create(&contact)
+	}
+
+	tests := []struct {
+		url   string
+		total int64
+	}{
+		{"/api/v1/contacts?tag=vip", 2},
+		{"/api/v1/contacts?tag=vip&tag=supplier", 2},
+		{"/api/v1/contacts?tag=vip&tag=supplier&tag_mode=all", 1},
+		{"/api/v1/contacts/search?q=silva&mode=phonetic&tag=supplier", 1},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", tt.url, nil)
+		resp, err := app.Test(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode, tt.url)
+
+		var response models.PaginatedResponse
+		err = json.NewDecoder(resp.Body).Decode(&response)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.total, response.Total, tt.url)
+	}
+
+	req := httptest.NewRequest("GET", "/api/v1/contacts?tag=vip&tag_mode=some", nil)
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 400, resp.StatusCode)
+}